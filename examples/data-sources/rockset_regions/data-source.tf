@@ -0,0 +1,5 @@
+data "rockset_regions" "all" {}
+
+output "frankfurt" {
+  value = data.rockset_regions.all.api_servers["euc1a1"]
+}
@@ -0,0 +1,2 @@
+# the import id is <name>, or <name>:<user> for a key created for another user
+terraform import rockset_api_key.demo demo:user@example.com
\ No newline at end of file
@@ -0,0 +1,2 @@
+# the import id is the <virtual_instance_id>
+terraform import rockset_autoscaling_policy.main 29e4a43c-fff4-4fe6-80e3-1ee57bc22e82
\ No newline at end of file
@@ -0,0 +1,2 @@
+# the import id is <workspace>.<name>
+terraform import rockset_collection.demo commons.demo
\ No newline at end of file
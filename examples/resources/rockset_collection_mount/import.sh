@@ -1 +1,2 @@
-terraform import rockset_collection_mount.demo 29e4a43c-fff4-4fe6-80e3-1ee57bc22e82
\ No newline at end of file
+# the import id is <workspace>.<collection>:<virtual_instance_id>
+terraform import rockset_collection_mount.demo commons.demo:29e4a43c-fff4-4fe6-80e3-1ee57bc22e82
\ No newline at end of file
@@ -0,0 +1,2 @@
+# the import id is <workspace>.<collection>.<source id>
+terraform import rockset_collection_source.orders_2023 commons.orders.eafa74f8-d59d-4d7e-9f7e-efa50810d8b8
\ No newline at end of file
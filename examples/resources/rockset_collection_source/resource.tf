@@ -0,0 +1,15 @@
+resource rockset_collection orders {
+  workspace = "commons"
+  name      = "orders"
+}
+
+resource rockset_collection_source orders_2023 {
+  workspace        = rockset_collection.orders.workspace
+  collection       = rockset_collection.orders.name
+  integration_name = "s3-orders"
+
+  s3 {
+    bucket = "orders"
+    prefix = "2023/"
+  }
+}
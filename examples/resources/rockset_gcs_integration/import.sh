@@ -0,0 +1,2 @@
+# the import id is <name>
+terraform import rockset_gcs_integration.demo demo
\ No newline at end of file
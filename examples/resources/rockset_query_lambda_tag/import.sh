@@ -0,0 +1,2 @@
+# the import id is <workspace>.<query_lambda>.<tag>
+terraform import rockset_query_lambda_tag.demo commons.demo.latest
\ No newline at end of file
@@ -0,0 +1,2 @@
+# the import id is <workspace>.<rrn>
+terraform import rockset_scheduled_lambda.demo commons.rrn:sl:usw2a1:4a2c8f7e-5b1d-4c3e-9f6a-8d7b2e1c0a93
\ No newline at end of file
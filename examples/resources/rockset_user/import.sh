@@ -0,0 +1,2 @@
+# the import id is <email>
+terraform import rockset_user.demo user@example.com
\ No newline at end of file
@@ -1 +1,2 @@
+# the import id is <id>
 terraform import rockset_virtual_instance.query 29e4a43c-fff4-4fe6-80e3-1ee57bc22e82
\ No newline at end of file
@@ -1 +1,2 @@
+# the import id is <name>
 terraform import rockset_workspace.demo demo
\ No newline at end of file
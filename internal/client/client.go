@@ -0,0 +1,164 @@
+// Package client creates the Rockset client shared by the SDKv2 and the plugin framework halves of the provider,
+// so both are configured the same way.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/retry"
+	"github.com/rockset/rockset-go-client/wait"
+
+	"github.com/rockset/terraform-provider-rockset/internal/tracing"
+)
+
+const providerUserAgent = "terraform-provider-rockset"
+
+// ErrReadOnly is returned when trying to change a resource while the provider is configured with read_only = true.
+var ErrReadOnly = errors.New("the provider is configured with read_only = true")
+
+// BaseTransport sends the requests to the Rockset API when it is set, and otherwise the default transport is used.
+// It only is set in tests, e.g. to trust the certificate of a rocksettest.Server.
+var BaseTransport http.RoundTripper
+
+// Config is the provider configuration needed to create a Rockset client.
+type Config struct {
+	APIKey    string
+	APIServer string
+	// Region is the Rockset region to connect to, which can't be used together with APIServer.
+	Region string
+	// OrgID is the organization the API key is expected to belong to.
+	OrgID string
+	// APIKeyCommand is a command which outputs the API key.
+	APIKeyCommand string
+	// Profile is the name of the context to read from the CredentialsFile.
+	Profile         string
+	CredentialsFile string
+	// Version is the provider version, which is added to the user agent.
+	Version string
+	Retry   RetryConfig
+}
+
+// New creates a Rockset client from the Config, which retries and throttles every API call made through it.
+// If neither an API server nor a region is configured, the API server is discovered from the clusters of the
+// organization. If an organization ID is configured, it validates that the API key belongs to it.
+func New(ctx context.Context, cfg Config) (*rockset.RockClient, error) {
+	if cfg.Region != "" {
+		if cfg.APIServer != "" {
+			return nil, errors.New("only one of api_server and region can be set")
+		}
+
+		server, err := RegionAPIServer(cfg.Region)
+		if err != nil {
+			return nil, err
+		}
+		cfg.APIServer = server
+	}
+
+	cfg, err := resolveCredentials(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.APIServer == "" && os.Getenv(rockset.APIServerEnvironmentVariableName) == "" {
+		server, err := discoverAPIServer(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		cfg.APIServer = server
+	}
+
+	rc, err := newRockClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.OrgID != "" {
+		org, err := rc.GetOrganization(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if org.GetId() != cfg.OrgID {
+			return nil, fmt.Errorf(
+				"the organization configured in the provider `%s` does not match the organization of the api key: `%s`",
+				cfg.OrgID, org.GetId())
+		}
+	}
+
+	return rc, nil
+}
+
+// discoverAPIServer reads the organization from the API server of each region, starting with the DefaultRegion,
+// as an API key only is valid in the region it was created in, and finds the API server from its clusters.
+func discoverAPIServer(ctx context.Context, cfg Config) (string, error) {
+	ids := []string{DefaultRegion}
+	for _, id := range RegionIDs() {
+		if id != DefaultRegion {
+			ids = append(ids, id)
+		}
+	}
+
+	var errs []error
+	for _, id := range ids {
+		bootstrap := cfg
+		bootstrap.APIServer = Regions[id].APIServer
+
+		rc, err := newRockClient(bootstrap)
+		if err != nil {
+			return "", err
+		}
+
+		org, err := rc.GetOrganization(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", id, err))
+			continue
+		}
+
+		server, err := apiServerFromClusters(org.Clusters)
+		if err != nil {
+			return "", fmt.Errorf("failed to discover the API server: %w", err)
+		}
+
+		return server, nil
+	}
+
+	return "", fmt.Errorf("failed to discover the API server, as the organization can't be read in any region, "+
+		"set the region or api_server of the provider: %w", errors.Join(errs...))
+}
+
+func newRockClient(cfg Config) (*rockset.RockClient, error) {
+	var opts = []rockset.RockOption{
+		rockset.WithUserAgent(fmt.Sprintf("%s/%s", providerUserAgent, cfg.Version)),
+		// the transport retries the API calls, so the go client must not retry them too, as it otherwise
+		// keeps retrying rate limited calls until the context is cancelled
+		rockset.WithRetry(retry.Exponential{RetryableErrorCheck: func(error) bool { return false }}),
+	}
+
+	if cfg.APIKey != "" {
+		opts = append(opts, rockset.WithAPIKey(cfg.APIKey))
+	}
+
+	if cfg.APIServer != "" {
+		opts = append(opts, rockset.WithAPIServer(cfg.APIServer))
+	}
+
+	rc, err := rockset.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := rc.GetConfig().HTTPClient
+	if BaseTransport != nil {
+		httpClient.Transport = BaseTransport
+	}
+	// the logging and tracing transports are below the retrying transport, so every attempt is logged and traced
+	httpClient.Transport = NewTransport(NewLoggingTransport(tracing.NewTransport(httpClient.Transport)), cfg.Retry)
+	rc.Wait = wait.New(tracing.WaitResourceGetter(rc))
+
+	return rc, nil
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ProfileEnv is the environment variable used to select the profile when it isn't set in the provider block.
+	ProfileEnv = "ROCKSET_PROFILE"
+	// CredentialsFileEnv is the environment variable used to override the default credentials file location.
+	CredentialsFileEnv = "ROCKSET_CREDENTIALS_FILE"
+
+	apiKeyCommandTimeout = time.Minute
+)
+
+// Profile is a named context in the credentials file.
+type Profile struct {
+	APIKey    string `yaml:"api_key"`
+	APIServer string `yaml:"api_server"`
+	OrgID     string `yaml:"organization_id"`
+}
+
+// CredentialsFile is the format of the credentials file, e.g.
+//
+//	contexts:
+//	  dev:
+//	    api_key: ...
+//	    api_server: api.usw2a1.rockset.com
+//	    organization_id: ...
+type CredentialsFile struct {
+	Contexts map[string]Profile `yaml:"contexts"`
+}
+
+// DefaultCredentialsFile returns the location of the credentials file, which is ~/.config/rockset/credentials.yaml
+// unless overridden using the ROCKSET_CREDENTIALS_FILE environment variable.
+func DefaultCredentialsFile() (string, error) {
+	if f := os.Getenv(CredentialsFileEnv); f != "" {
+		return f, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config", "rockset", "credentials.yaml"), nil
+}
+
+// LoadProfile reads the named profile from the credentials file.
+func LoadProfile(file, name string) (Profile, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var creds CredentialsFile
+	if err = yaml.Unmarshal(data, &creds); err != nil {
+		return Profile{}, fmt.Errorf("failed to parse credentials file %s: %w", file, err)
+	}
+
+	profile, found := creds.Contexts[name]
+	if !found {
+		return Profile{}, fmt.Errorf("profile %s not found in credentials file %s", name, file)
+	}
+
+	return profile, nil
+}
+
+// resolveCredentials fills in the API key, API server and organization ID which aren't set explicitly in the
+// provider configuration, first by running the APIKeyCommand and then from the profile. Anything still not set
+// is left for the Rockset go client to read from the environment.
+func resolveCredentials(ctx context.Context, cfg Config) (Config, error) {
+	if cfg.APIKey != "" && cfg.APIKeyCommand != "" {
+		return cfg, errors.New("only one of api_key and api_key_command can be set")
+	}
+
+	if cfg.APIKeyCommand != "" {
+		key, err := runAPIKeyCommand(ctx, cfg.APIKeyCommand)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.APIKey = key
+	}
+
+	profile := cfg.Profile
+	if profile == "" {
+		profile = os.Getenv(ProfileEnv)
+	}
+	if profile == "" {
+		return cfg, nil
+	}
+
+	file := cfg.CredentialsFile
+	if file == "" {
+		var err error
+		if file, err = DefaultCredentialsFile(); err != nil {
+			return cfg, err
+		}
+	}
+
+	p, err := LoadProfile(file, profile)
+	if err != nil {
+		return cfg, err
+	}
+
+	if cfg.APIKey == "" {
+		cfg.APIKey = p.APIKey
+	}
+	if cfg.APIServer == "" {
+		cfg.APIServer = p.APIServer
+	}
+	if cfg.OrgID == "" {
+		cfg.OrgID = p.OrgID
+	}
+
+	return cfg, nil
+}
+
+// runAPIKeyCommand runs the command using the shell, and returns its output as the API key.
+func runAPIKeyCommand(ctx context.Context, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// the output might contain the key, so only stderr is included in the error
+		return "", fmt.Errorf("api_key_command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", errors.New("api_key_command did not output an API key")
+	}
+
+	return key, nil
+}
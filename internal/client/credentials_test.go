@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCredentials = `
+contexts:
+  dev:
+    api_key: dev-key
+    api_server: api.usw2a1.rockset.com
+    organization_id: dev-org
+  prod:
+    api_key: prod-key
+    api_server: api.use1a1.rockset.com
+`
+
+func writeCredentials(t *testing.T) string {
+	file := filepath.Join(t.TempDir(), "credentials.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testCredentials), 0600))
+
+	return file
+}
+
+func TestLoadProfile(t *testing.T) {
+	file := writeCredentials(t)
+
+	p, err := LoadProfile(file, "dev")
+	require.NoError(t, err)
+	assert.Equal(t, Profile{APIKey: "dev-key", APIServer: "api.usw2a1.rockset.com", OrgID: "dev-org"}, p)
+
+	_, err = LoadProfile(file, "staging")
+	assert.ErrorContains(t, err, "profile staging not found")
+
+	_, err = LoadProfile(filepath.Join(t.TempDir(), "missing.yaml"), "dev")
+	assert.Error(t, err)
+}
+
+func TestResolveCredentials(t *testing.T) {
+	ctx := context.TODO()
+	file := writeCredentials(t)
+	t.Setenv(ProfileEnv, "")
+
+	t.Run("no profile", func(t *testing.T) {
+		cfg, err := resolveCredentials(ctx, Config{APIKey: "key"})
+		require.NoError(t, err)
+		assert.Equal(t, Config{APIKey: "key"}, cfg)
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		cfg, err := resolveCredentials(ctx, Config{Profile: "dev", CredentialsFile: file})
+		require.NoError(t, err)
+		assert.Equal(t, "dev-key", cfg.APIKey)
+		assert.Equal(t, "api.usw2a1.rockset.com", cfg.APIServer)
+		assert.Equal(t, "dev-org", cfg.OrgID)
+	})
+
+	t.Run("explicit arguments take precedence", func(t *testing.T) {
+		cfg, err := resolveCredentials(ctx, Config{
+			APIServer:       "api.euc1a1.rockset.com",
+			Profile:         "prod",
+			CredentialsFile: file,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "prod-key", cfg.APIKey)
+		assert.Equal(t, "api.euc1a1.rockset.com", cfg.APIServer)
+	})
+
+	t.Run("profile from environment", func(t *testing.T) {
+		t.Setenv(ProfileEnv, "prod")
+		t.Setenv(CredentialsFileEnv, file)
+
+		cfg, err := resolveCredentials(ctx, Config{})
+		require.NoError(t, err)
+		assert.Equal(t, "prod-key", cfg.APIKey)
+	})
+
+	t.Run("api key and command", func(t *testing.T) {
+		_, err := resolveCredentials(ctx, Config{APIKey: "key", APIKeyCommand: "echo key"})
+		assert.Error(t, err)
+	})
+}
+
+func TestRunAPIKeyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a unix shell")
+	}
+	ctx := context.TODO()
+
+	cfg, err := resolveCredentials(ctx, Config{APIKeyCommand: "echo ' command-key '"})
+	require.NoError(t, err)
+	assert.Equal(t, "command-key", cfg.APIKey)
+
+	_, err = runAPIKeyCommand(ctx, "echo oops >&2; exit 1")
+	assert.ErrorContains(t, err, "oops")
+
+	_, err = runAPIKeyCommand(ctx, "true")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"errors"
+
+	rockerr "github.com/rockset/rockset-go-client/errors"
+)
+
+// ErrNotFound is returned when an object which is looked up by listing all objects of its kind doesn't exist,
+// as there is no API error then.
+var ErrNotFound = errors.New("not found")
+
+// IsNotFound returns true if err is a Rockset API error for an object which doesn't exist, e.g. because it was
+// deleted outside of Terraform, or if it is an ErrNotFound.
+func IsNotFound(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+
+	var re rockerr.Error
+	if !errors.As(err, &re) {
+		return false
+	}
+
+	return re.IsNotFoundError()
+}
+
+// IsPermanent returns true if err is a Rockset API error which retrying the request won't resolve, which are the
+// client errors other than for an object which doesn't exist (yet) or for being rate limited.
+func IsPermanent(err error) bool {
+	var re rockerr.Error
+	if !errors.As(err, &re) || re.ErrorModel == nil {
+		return false
+	}
+
+	return re.StatusCode >= 400 && re.StatusCode < 500 && !re.IsNotFoundError() && !re.Retryable()
+}
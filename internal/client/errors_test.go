@@ -0,0 +1,38 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func apiError(status int) error {
+	err := rockerr.NewWithStatusCode(errors.New("apierr"), &http.Response{StatusCode: status})
+	re := err.(rockerr.Error)
+	re.ErrorModel = &openapi.ErrorModel{Message: openapi.PtrString(http.StatusText(status))}
+
+	return re
+}
+
+func TestIsNotFound(t *testing.T) {
+	assert.True(t, IsNotFound(apiError(http.StatusNotFound)))
+	assert.True(t, IsNotFound(fmt.Errorf("failed to read: %w", apiError(http.StatusNotFound))))
+	assert.True(t, IsNotFound(fmt.Errorf("query lambda ql %w", ErrNotFound)))
+	assert.False(t, IsNotFound(apiError(http.StatusBadRequest)))
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsNotFound(nil))
+}
+
+func TestIsPermanent(t *testing.T) {
+	assert.True(t, IsPermanent(apiError(http.StatusBadRequest)))
+	assert.True(t, IsPermanent(fmt.Errorf("query failed: %w", apiError(http.StatusForbidden))))
+	assert.False(t, IsPermanent(apiError(http.StatusNotFound)))
+	assert.False(t, IsPermanent(apiError(http.StatusTooManyRequests)))
+	assert.False(t, IsPermanent(apiError(http.StatusServiceUnavailable)))
+	assert.False(t, IsPermanent(errors.New("connection refused")))
+}
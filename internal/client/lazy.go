@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rockset/rockset-go-client"
+)
+
+// Lazy creates the Rockset client the first time it is needed, so configuring the provider doesn't require
+// network access, which lets `terraform validate` and plans with credentials unknown until apply work.
+type Lazy struct {
+	cfg Config
+	mu  sync.Mutex
+	rc  *rockset.RockClient
+}
+
+// NewLazy returns a Lazy which creates the client from cfg.
+func NewLazy(cfg Config) *Lazy {
+	return &Lazy{cfg: cfg}
+}
+
+// Client returns the client, which is created by the first call which succeeds. If it fails to create the client,
+// e.g. because the API server couldn't be discovered, the error is returned and the next call tries again.
+func (l *Lazy) Client(ctx context.Context) (*rockset.RockClient, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rc != nil {
+		return l.rc, nil
+	}
+
+	// the client outlives the operation which happens to create it, so it must not be cancelled with it
+	rc, err := New(context.WithoutCancel(ctx), l.cfg)
+	if err != nil {
+		return nil, err
+	}
+	l.rc = rc
+
+	return rc, nil
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   = make(map[Config]*Lazy)
+)
+
+// Shared returns the Lazy for cfg, which is the same for all calls with an identical configuration. As the SDKv2 and
+// the plugin framework halves of the provider receive the same configuration, they share a single client, and the
+// organization is only validated once per process, once it succeeds.
+func Shared(cfg Config) *Lazy {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	// the halves know the provider version differently, which only is used in the user agent
+	key := cfg
+	key.Version = ""
+
+	l, found := shared[key]
+	if !found {
+		l = NewLazy(cfg)
+		shared[key] = l
+	}
+
+	return l
+}
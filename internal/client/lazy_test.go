@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestShared(t *testing.T) {
+	cfg := Config{APIKey: "key", APIServer: "api.usw2a1.rockset.com", Version: "1.0.0"}
+
+	l := Shared(cfg)
+	assert.Same(t, l, Shared(cfg))
+
+	// the SDKv2 and plugin framework providers know the version differently
+	cfg.Version = "test"
+	assert.Same(t, l, Shared(cfg))
+
+	cfg.APIKey = "other"
+	assert.NotSame(t, l, Shared(cfg))
+}
+
+func TestLazy_Client(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	base := BaseTransport
+	BaseTransport = srv.Client().Transport
+	defer func() { BaseTransport = base }()
+
+	// the command fails the first time it is run, e.g. like a transient failure to fetch the API key
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	l := NewLazy(Config{
+		APIServer: srv.URL,
+		APIKeyCommand: fmt.Sprintf("echo call >> %s && test -f %s && echo %s || { touch %s; exit 1; }",
+			calls, filepath.Join(dir, "failed"), rocksettest.APIKey, filepath.Join(dir, "failed")),
+	})
+
+	rc, err := l.Client(ctx)
+	assert.ErrorContains(t, err, "api_key_command")
+	assert.Nil(t, rc)
+
+	// a failure isn't cached, so the next call creates the client, which is then returned by every call
+	rc, err = l.Client(ctx)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		again, err := l.Client(ctx)
+		require.NoError(t, err)
+		assert.Same(t, rc, again)
+	}
+
+	data, err := os.ReadFile(calls)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "call"))
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// LogSubsystem is the tflog subsystem the API requests are logged to.
+	LogSubsystem = "rockset-api"
+	// LogLevelEnv is the environment variable which sets the log level of the LogSubsystem.
+	LogLevelEnv = "TF_LOG_PROVIDER_ROCKSET_API"
+
+	// maxLoggedBody is the number of bytes of a request or response body which are logged.
+	maxLoggedBody = 16 * 1024
+	masked        = "***"
+)
+
+// SecretFields are fields in request and response bodies which contain secrets, which are masked when logged,
+// and redacted from the cassettes of the acceptance tests.
+var SecretFields = []string{
+	"key",    // the API key in the create API key response
+	"secret", // security_config.secret of kafka integrations
+	"api_key",
+	"connection_uri",
+	"service_account_key_file_json",
+	"webhook_auth_header",
+	"aws_secret_access_key",
+	"password",
+}
+
+// secretHeaders are headers which contain secrets, and which are masked when logged.
+var secretHeaders = []string{"Authorization"}
+
+// LoggingTransport is a http.RoundTripper which logs every request to the Rockset API to the LogSubsystem,
+// with secrets masked. Requests are logged at debug level, and at trace level the headers and bodies are included.
+type LoggingTransport struct {
+	base  http.RoundTripper
+	level hclog.Level
+}
+
+// NewLoggingTransport wraps base in a LoggingTransport, if base is nil http.DefaultTransport is used.
+func NewLoggingTransport(base http.RoundTripper) *LoggingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	level := hclog.LevelFromString(os.Getenv(LogLevelEnv))
+	// ROCKSET_DEBUG used to dump the raw requests to stderr, now it logs them with secrets masked
+	if level == hclog.NoLevel && os.Getenv("ROCKSET_DEBUG") == "true" {
+		level = hclog.Trace
+	}
+
+	return &LoggingTransport{
+		base:  base,
+		level: level,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevel(t.level))
+
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"path":   req.URL.Path,
+	}
+
+	var body []byte
+	if req.Body != nil && req.GetBody != nil {
+		if r, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(io.LimitReader(r, maxLoggedBody))
+			_ = r.Close()
+		}
+	}
+	tflog.SubsystemTrace(ctx, LogSubsystem, "sending Rockset API request", map[string]interface{}{
+		"method":  req.Method,
+		"path":    req.URL.Path,
+		"query":   req.URL.RawQuery,
+		"headers": maskHeaders(req.Header),
+		"body":    maskBody(body),
+	})
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	fields["latency"] = time.Since(start).String()
+
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "Rockset API request failed", fields)
+		return resp, err
+	}
+	fields["status_code"] = resp.StatusCode
+
+	// the start of the body is read so the trace ID can be extracted from errors and logged, and then put back in
+	// front of the rest of the body for the caller, so large responses aren't buffered
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBody))
+	resp.Body = prefixedBody{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), Closer: resp.Body}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, LogSubsystem, "Rockset API request failed", fields)
+		return resp, err
+	}
+
+	if traceID := traceID(data); traceID != "" {
+		fields["trace_id"] = traceID
+	}
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Rockset API request", fields)
+
+	tflog.SubsystemTrace(ctx, LogSubsystem, "Rockset API response", map[string]interface{}{
+		"method":      req.Method,
+		"path":        req.URL.Path,
+		"status_code": resp.StatusCode,
+		"headers":     maskHeaders(resp.Header),
+		"body":        maskBody(data),
+	})
+
+	return resp, nil
+}
+
+// prefixedBody is a response body of which the start has already been read.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// traceID extracts the trace ID from an error response.
+func traceID(body []byte) string {
+	var e struct {
+		TraceID string `json:"trace_id"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return ""
+	}
+
+	return e.TraceID
+}
+
+func maskHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for k := range h {
+		headers[k] = h.Get(k)
+	}
+
+	for _, s := range secretHeaders {
+		if _, found := headers[s]; found {
+			headers[s] = masked
+		}
+	}
+
+	return headers
+}
+
+// maskBody masks the SecretFields in a JSON body. Bodies which can't be parsed, e.g. because they were truncated,
+// aren't logged as they could contain secrets.
+func maskBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "<omitted non-JSON body>"
+	}
+
+	data, err := json.Marshal(maskValue(v))
+	if err != nil {
+		return "<omitted non-JSON body>"
+	}
+
+	return string(data)
+}
+
+func maskValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, e := range value {
+			if slices.Contains(SecretFields, k) {
+				value[k] = masked
+			} else {
+				value[k] = maskValue(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range value {
+			value[i] = maskValue(e)
+		}
+	}
+
+	return v
+}
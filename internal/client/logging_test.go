@@ -0,0 +1,142 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingTransport(t *testing.T) {
+	t.Setenv(LogLevelEnv, "TRACE")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "bad request", "trace_id": "trace-123", "key": "the-api-key"}`))
+	}))
+	defer ts.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	body := `{"name": "kafka", "kafka": {"security_config": {"api_key": "id", "secret": "kafka-secret"}}}`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+"/v1/orgs/self/integrations",
+		strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "apikey the-api-key")
+
+	c := http.Client{Transport: NewLoggingTransport(nil)}
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// the response body must still be readable
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "the-api-key")
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.NotContains(t, output.String(), "the-api-key")
+	assert.NotContains(t, output.String(), "kafka-secret")
+
+	request := entries[0]
+	assert.Equal(t, "sending Rockset API request", request["@message"])
+	assert.True(t, strings.HasSuffix(request["@module"].(string), LogSubsystem))
+	assert.Equal(t, masked, request["headers"].(map[string]interface{})["Authorization"])
+	assert.Contains(t, request["body"], `"secret":"***"`)
+
+	summary := entries[1]
+	assert.Equal(t, "Rockset API request", summary["@message"])
+	assert.Equal(t, "debug", summary["@level"])
+	assert.Equal(t, "POST", summary["method"])
+	assert.Equal(t, "/v1/orgs/self/integrations", summary["path"])
+	assert.Equal(t, float64(http.StatusBadRequest), summary["status_code"])
+	assert.Equal(t, "trace-123", summary["trace_id"])
+	assert.Contains(t, summary, "latency")
+
+	response := entries[2]
+	assert.Equal(t, "Rockset API response", response["@message"])
+	assert.Contains(t, response["body"], `"key":"***"`)
+}
+
+func TestLoggingTransport_Level(t *testing.T) {
+	t.Setenv(LogLevelEnv, "DEBUG")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+
+	c := http.Client{Transport: NewLoggingTransport(nil)}
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "only the debug entry should be logged")
+	assert.Equal(t, "Rockset API request", entries[0]["@message"])
+}
+
+// countingTransport responds with a body of size bytes, and counts how many of them have been read.
+type countingTransport struct {
+	size int
+	read int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(io.TeeReader(bytes.NewReader(make([]byte, t.size)), countingWriter{t})),
+		Request:    req,
+	}, nil
+}
+
+type countingWriter struct{ t *countingTransport }
+
+func (w countingWriter) Write(p []byte) (int, error) {
+	w.t.read += len(p)
+	return len(p), nil
+}
+
+func TestLoggingTransport_LargeResponse(t *testing.T) {
+	t.Setenv(LogLevelEnv, "TRACE")
+	ctx := tflogtest.RootLogger(context.Background(), io.Discard)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://rockset.test/v1/orgs/self", nil)
+	require.NoError(t, err)
+
+	base := &countingTransport{size: 4 * maxLoggedBody}
+	resp, err := NewLoggingTransport(base).RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// only the part which is logged is read by the transport, the rest is streamed to the caller
+	assert.LessOrEqual(t, base.read, maxLoggedBody)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Len(t, data, 4*maxLoggedBody)
+}
+
+func TestMaskBody(t *testing.T) {
+	assert.Equal(t, "", maskBody(nil))
+	assert.Equal(t, "<omitted non-JSON body>", maskBody([]byte(`{"connection_uri": "mongodb+srv://user:pass@`)))
+	assert.Equal(t, `{"mongodb":{"connection_uri":"***"},"sources":[{"webhook_auth_header":"***"}]}`,
+		maskBody([]byte(`{"mongodb": {"connection_uri": "uri"}, "sources": [{"webhook_auth_header": "h"}]}`)))
+}
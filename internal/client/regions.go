@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+// DefaultRegion is the region used to discover the API server of the organization,
+// when neither an API server nor a region is configured.
+const DefaultRegion = "usw2a1"
+
+// Region is a Rockset region.
+type Region struct {
+	// ID is the Rockset region ID, e.g. usw2a1.
+	ID string
+	// APIServer is the API server of the region.
+	APIServer string
+	// CloudRegion is the region of the cloud provider which hosts the Rockset region.
+	CloudRegion string
+	// Description is the human-readable name of the region.
+	Description string
+}
+
+// Regions are the Rockset regions, keyed by their ID.
+var Regions = map[string]Region{
+	"usw2a1": {
+		ID:          "usw2a1",
+		APIServer:   "api.usw2a1.rockset.com",
+		CloudRegion: "us-west-2",
+		Description: "Oregon (us-west-2)",
+	},
+	"use1a1": {
+		ID:          "use1a1",
+		APIServer:   "api.use1a1.rockset.com",
+		CloudRegion: "us-east-1",
+		Description: "N. Virginia (us-east-1)",
+	},
+	"euc1a1": {
+		ID:          "euc1a1",
+		APIServer:   "api.euc1a1.rockset.com",
+		CloudRegion: "eu-central-1",
+		Description: "Frankfurt (eu-central-1)",
+	},
+	"aps1a1": {
+		ID:          "aps1a1",
+		APIServer:   "api.aps1a1.rockset.com",
+		CloudRegion: "ap-south-1",
+		Description: "Mumbai (ap-south-1)",
+	},
+}
+
+// RegionIDs returns the sorted IDs of all Rockset regions.
+func RegionIDs() []string {
+	ids := make([]string, 0, len(Regions))
+	for id := range Regions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// RegionAPIServer returns the API server of the region.
+func RegionAPIServer(id string) (string, error) {
+	r, found := Regions[id]
+	if !found {
+		return "", fmt.Errorf("unknown Rockset region %s, valid regions are: %s", id,
+			strings.Join(RegionIDs(), ", "))
+	}
+
+	return r.APIServer, nil
+}
+
+// apiServerFromClusters returns the API server of the clusters, which only can be determined if there is exactly
+// one API server among them.
+func apiServerFromClusters(clusters []openapi.Cluster) (string, error) {
+	var servers []string
+	for _, c := range clusters {
+		server := hostname(c.GetApiserverUrl())
+		if server != "" && !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+
+	switch len(servers) {
+	case 0:
+		return "", errors.New("the organization has no clusters, set the region or api_server of the provider")
+	case 1:
+		return servers[0], nil
+	default:
+		return "", fmt.Errorf("the organization has clusters in multiple regions (%s), "+
+			"set the region or api_server of the provider", strings.Join(servers, ", "))
+	}
+}
+
+// hostname strips the scheme from the API server URL, if it has one and no port, as the Rockset client can't parse
+// a host with a port without the scheme.
+func hostname(server string) string {
+	if u, err := url.Parse(server); err == nil && u.Host != "" && u.Port() == "" {
+		return u.Host
+	}
+
+	return server
+}
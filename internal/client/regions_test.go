@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestRegionAPIServer(t *testing.T) {
+	server, err := RegionAPIServer("euc1a1")
+	require.NoError(t, err)
+	assert.Equal(t, "api.euc1a1.rockset.com", server)
+
+	_, err = RegionAPIServer("usw1a1")
+	assert.ErrorContains(t, err, "valid regions are: aps1a1, euc1a1, use1a1, usw2a1")
+}
+
+func cluster(server string) openapi.Cluster {
+	return openapi.Cluster{ApiserverUrl: &server}
+}
+
+func TestAPIServerFromClusters(t *testing.T) {
+	tests := []struct {
+		name     string
+		clusters []openapi.Cluster
+		expected string
+		err      bool
+	}{
+		{"no clusters", nil, "", true},
+		{"one cluster", []openapi.Cluster{cluster("https://api.use1a1.rockset.com")}, "api.use1a1.rockset.com", false},
+		{"same server", []openapi.Cluster{
+			cluster("https://api.euc1a1.rockset.com"),
+			cluster("api.euc1a1.rockset.com"),
+		}, "api.euc1a1.rockset.com", false},
+		{"port", []openapi.Cluster{cluster("https://127.0.0.1:8443")}, "https://127.0.0.1:8443", false},
+		{"multiple regions", []openapi.Cluster{
+			cluster("https://api.use1a1.rockset.com"),
+			cluster("https://api.usw2a1.rockset.com"),
+		}, "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := apiServerFromClusters(tc.clusters)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, server)
+		})
+	}
+}
+
+func TestNew_RegionAndAPIServer(t *testing.T) {
+	_, err := New(context.TODO(), Config{APIKey: "key", APIServer: "api.usw2a1.rockset.com", Region: "usw2a1"})
+	assert.ErrorContains(t, err, "only one of api_server and region")
+
+	_, err = New(context.TODO(), Config{APIKey: "key", Region: "mars1a1"})
+	assert.ErrorContains(t, err, "unknown Rockset region")
+}
+
+func TestNew_DiscoverAPIServer(t *testing.T) {
+	ctx := context.TODO()
+
+	// the API key is created in the organization of the fake in euc1a1, so it isn't valid in the default region
+	def := rocksettest.NewServer()
+	defer def.Close()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	base, regions := BaseTransport, Regions
+	BaseTransport = srv.Client().Transport
+	defer func() { BaseTransport, Regions = base, regions }()
+
+	key, err := rocksettest.NewClient(t, srv).CreateAPIKey(ctx, "euc1a1")
+	require.NoError(t, err)
+
+	Regions = map[string]Region{
+		DefaultRegion: {ID: DefaultRegion, APIServer: def.URL},
+		"euc1a1":      {ID: "euc1a1", APIServer: srv.URL},
+	}
+
+	rc, err := New(ctx, Config{APIKey: key.GetKey()})
+	require.NoError(t, err)
+	org, err := rc.GetOrganization(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rocksettest.OrganizationID, org.GetId())
+
+	_, err = New(ctx, Config{APIKey: "invalid"})
+	assert.ErrorContains(t, err, "set the region or api_server of the provider")
+}
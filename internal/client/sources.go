@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+// SourceSuspendedState is the state of a source which has been suspended.
+const SourceSuspendedState = "SUSPENDED"
+
+// ResumeAfterRe matches the durations a source can be suspended for, e.g. 30m or 12h.
+var ResumeAfterRe = regexp.MustCompile(`^[1-9][0-9]*[smh]$`)
+
+// IsSourceSuspended returns true if the source is suspended.
+func IsSourceSuspended(src openapi.Source) bool {
+	return src.Status.GetState() == SourceSuspendedState
+}
+
+// ResumedAutomatically returns true if a source which was suspended until resumeAt, as returned by the API, has been
+// resumed by Rockset rather than by someone else, as it only is resumed automatically once resumeAt has passed.
+func ResumedAutomatically(resumeAt string, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, resumeAt)
+	if err != nil {
+		return false
+	}
+
+	return !now.Before(t)
+}
+
+// SetSourceSuspended suspends or resumes a source, waits until its state has changed, and returns the source. A
+// suspended source is resumed by Rockset after resumeAfter, or after an hour if it is empty. Suspending a source
+// which already is suspended restarts the duration.
+func SetSourceSuspended(ctx context.Context, rc *rockset.RockClient, workspace, collection, id string,
+	suspended bool, resumeAfter string) (openapi.Source, error) {
+	var err error
+	var httpResp *http.Response
+	if suspended {
+		req := openapi.SuspendSourceRequest{}
+		if resumeAfter != "" {
+			req.ResumeAfterDuration = &resumeAfter
+		}
+		_, httpResp, err = rc.SourcesApi.SuspendSource(ctx, workspace, collection, id).Body(req).Execute()
+	} else {
+		_, httpResp, err = rc.SourcesApi.ResumeSource(ctx, workspace, collection, id).Execute()
+	}
+	if err != nil {
+		return openapi.Source{}, rockerr.NewWithStatusCode(err, httpResp)
+	}
+
+	var src openapi.Source
+	err = rc.RetryWithCheck(ctx, func() (bool, error) {
+		resp, httpResp, err := rc.SourcesApi.GetSource(ctx, workspace, collection, id).Execute()
+		if err != nil {
+			return false, rockerr.NewWithStatusCode(err, httpResp)
+		}
+		src = resp.GetData()
+
+		return IsSourceSuspended(src) != suspended, nil
+	})
+
+	return src, err
+}
+
+// SourceState describes the state of a source, for errors about a source which didn't change state in time.
+func SourceState(ctx context.Context, rc *rockset.RockClient, workspace, collection, id string) (string, error) {
+	resp, httpResp, err := rc.SourcesApi.GetSource(ctx, workspace, collection, id).Execute()
+	if err != nil {
+		return "", rockerr.NewWithStatusCode(err, httpResp)
+	}
+	src := resp.GetData()
+
+	if IsSourceSuspended(src) {
+		return fmt.Sprintf("%s until %s", SourceSuspendedState, src.GetResumeAt()), nil
+	}
+
+	return src.Status.GetState(), nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestSetSourceSuspended(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	_, err := rc.CreateCollection(ctx, "commons", "orders")
+	require.NoError(t, err)
+	created, _, err := rc.SourcesApi.CreateSource(ctx, "commons", "orders").Body(openapi.Source{
+		Kinesis: &openapi.SourceKinesis{StreamName: "orders"},
+	}).Execute()
+	require.NoError(t, err)
+	id := created.Data.GetId()
+
+	src, err := SetSourceSuspended(ctx, rc, "commons", "orders", id, true, "30m")
+	require.NoError(t, err)
+	assert.True(t, IsSourceSuspended(src))
+	assert.NotEmpty(t, src.GetResumeAt())
+	state, err := SourceState(ctx, rc, "commons", "orders", id)
+	require.NoError(t, err)
+	assert.Contains(t, state, "SUSPENDED until ")
+
+	src, err = SetSourceSuspended(ctx, rc, "commons", "orders", id, false, "")
+	require.NoError(t, err)
+	assert.False(t, IsSourceSuspended(src))
+	state, err = SourceState(ctx, rc, "commons", "orders", id)
+	require.NoError(t, err)
+	assert.NotEqual(t, SourceSuspendedState, state)
+
+	_, err = SetSourceSuspended(ctx, rc, "commons", "orders", "missing", true, "")
+	assert.True(t, IsNotFound(err))
+}
+
+func TestResumedAutomatically(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, ResumedAutomatically("2024-01-31T11:00:00Z", now))
+	assert.True(t, ResumedAutomatically("2024-01-31T12:00:00Z", now))
+	assert.False(t, ResumedAutomatically("2024-01-31T13:00:00Z", now))
+	assert.False(t, ResumedAutomatically("", now))
+}
+
+func TestResumeAfterRe(t *testing.T) {
+	for _, d := range []string{"30s", "15m", "12h"} {
+		assert.True(t, ResumeAfterRe.MatchString(d), d)
+	}
+	for _, d := range []string{"", "0h", "1h30m", "1d", "h"} {
+		assert.False(t, ResumeAfterRe.MatchString(d), d)
+	}
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultMaxRetries = 5
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryConfig configures how calls to the Rockset API are retried and throttled.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, which is doubled for each subsequent retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the longest wait between two retries, unless the API asks for a longer wait using Retry-After.
+	MaxBackoff time.Duration
+	// MaxConcurrentRequests limits the number of requests in flight, 0 means no limit.
+	MaxConcurrentRequests int
+	// RequestsPerSecond limits the rate of requests, 0 means no limit.
+	RequestsPerSecond float64
+}
+
+// DefaultRetryConfig returns the RetryConfig used when the provider configuration doesn't specify any values.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// Transport is a http.RoundTripper which throttles requests to the Rockset API,
+// and retries requests which fail with a retryable status code.
+type Transport struct {
+	base    http.RoundTripper
+	cfg     RetryConfig
+	sem     chan struct{}
+	limiter *rate.Limiter
+}
+
+// NewTransport wraps base in a Transport, if base is nil http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper, cfg RetryConfig) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t := Transport{
+		base: base,
+		cfg:  cfg,
+	}
+
+	if cfg.MaxConcurrentRequests > 0 {
+		t.sem = make(chan struct{}, cfg.MaxConcurrentRequests)
+	}
+
+	if cfg.RequestsPerSecond > 0 {
+		burst := int(cfg.RequestsPerSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		t.limiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst)
+	}
+
+	return &t
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTrip(req)
+		if err != nil || attempt >= t.cfg.MaxRetries || !retryable(req, resp) {
+			return resp, err
+		}
+
+		// the request body has already been consumed, so it can only be retried if it can be recreated
+		retryReq, err := rewind(req)
+		if err != nil {
+			return resp, nil
+		}
+
+		delay := t.backoff(attempt, resp)
+		tflog.Debug(ctx, "retrying Rockset API request", map[string]interface{}{
+			"method":      req.Method,
+			"path":        req.URL.Path,
+			"status_code": resp.StatusCode,
+			"attempt":     attempt + 1,
+			"delay":       delay.String(),
+		})
+
+		// discard the response so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if err = sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+		req = retryReq
+	}
+}
+
+// roundTrip makes a single request once the throttling limits allow it.
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if t.limiter != nil {
+		if err := t.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.sem != nil {
+		select {
+		case t.sem <- struct{}{}:
+			defer func() { <-t.sem }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// backoff returns how long to wait before the next attempt, which is the Retry-After header if the API sent one,
+// or an exponentially increasing and jittered delay between MinBackoff and MaxBackoff.
+func (t *Transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if d, ok := retryAfter(resp); ok {
+		return d
+	}
+
+	d := t.cfg.MinBackoff << attempt
+	if d <= 0 || d > t.cfg.MaxBackoff {
+		d = t.cfg.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// add up to 10% jitter, so concurrent requests which were rate limited together don't retry together
+	return d + time.Duration(rand.Int63n(int64(d)/10+1))
+}
+
+// transientStatusCodes are server errors which the Rockset API can return for transient problems, but the request
+// might have been processed, so they are only retried for idempotent requests.
+var transientStatusCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+}
+
+// retryable checks if the response should be retried. Responses which the Rockset go client considers retryable,
+// i.e. the status codes in rockerr.RetryableErrors, are always retried, and transient server errors are retried
+// for idempotent requests.
+func retryable(req *http.Request, resp *http.Response) bool {
+	if slices.Contains(rockerr.RetryableErrors, resp.StatusCode) {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return slices.Contains(transientStatusCodes, resp.StatusCode)
+	}
+
+	return false
+}
+
+// retryAfter parses the Retry-After header, which either is a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+var errNotRewindable = errors.New("request body can't be recreated")
+
+// rewind returns a copy of the request with a new body, so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return r, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errNotRewindable
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = body
+
+	return r, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
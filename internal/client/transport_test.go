@@ -0,0 +1,204 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 3,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 10 * time.Millisecond,
+	}
+}
+
+// statusServer responds with the status codes in order, and with 200 once they are used up.
+func statusServer(t *testing.T, codes ...int) (*httptest.Server, *int32) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		if r.Body != nil {
+			body, _ := io.ReadAll(r.Body)
+			if r.Method == http.MethodPost {
+				assert.Equal(t, "payload", string(body))
+			}
+		}
+		if n <= len(codes) {
+			w.WriteHeader(codes[n-1])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts, &calls
+}
+
+func TestTransport_RetriesRetryableErrors(t *testing.T) {
+	ts, calls := statusServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	c := http.Client{Transport: NewTransport(nil, testRetryConfig())}
+
+	resp, err := c.Post(ts.URL, "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	ts, calls := statusServer(t, 429, 429, 429, 429, 429, 429)
+	c := http.Client{Transport: NewTransport(nil, testRetryConfig())}
+
+	resp, err := c.Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+}
+
+func TestTransport_ZeroRetries(t *testing.T) {
+	ts, calls := statusServer(t, http.StatusServiceUnavailable)
+	cfg := testRetryConfig()
+	cfg.MaxRetries = 0
+	c := http.Client{Transport: NewTransport(nil, cfg)}
+
+	resp, err := c.Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestTransport_TransientErrors(t *testing.T) {
+	t.Run("idempotent", func(t *testing.T) {
+		ts, calls := statusServer(t, http.StatusInternalServerError)
+		c := http.Client{Transport: NewTransport(nil, testRetryConfig())}
+
+		resp, err := c.Get(ts.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	})
+
+	t.Run("not idempotent", func(t *testing.T) {
+		ts, calls := statusServer(t, http.StatusInternalServerError)
+		c := http.Client{Transport: NewTransport(nil, testRetryConfig())}
+
+		resp, err := c.Post(ts.URL, "text/plain", strings.NewReader("payload"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+}
+
+func TestTransport_DoesNotRetryClientErrors(t *testing.T) {
+	ts, calls := statusServer(t, http.StatusBadRequest)
+	c := http.Client{Transport: NewTransport(nil, testRetryConfig())}
+
+	resp, err := c.Get(ts.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestTransport_MaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer ts.Close()
+
+	cfg := testRetryConfig()
+	cfg.MaxConcurrentRequests = 2
+	c := http.Client{Transport: NewTransport(nil, cfg)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Get(ts.URL)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func TestTransport_Backoff(t *testing.T) {
+	tr := NewTransport(nil, RetryConfig{MinBackoff: time.Second, MaxBackoff: 5 * time.Second})
+	resp := &http.Response{Header: http.Header{}}
+
+	d := tr.backoff(0, resp)
+	assert.GreaterOrEqual(t, d, time.Second)
+	assert.LessOrEqual(t, d, 1100*time.Millisecond)
+
+	d = tr.backoff(2, resp)
+	assert.GreaterOrEqual(t, d, 4*time.Second)
+	assert.LessOrEqual(t, d, 4400*time.Millisecond)
+
+	d = tr.backoff(10, resp)
+	assert.GreaterOrEqual(t, d, 5*time.Second)
+	assert.LessOrEqual(t, d, 5500*time.Millisecond)
+
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, tr.backoff(0, resp))
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value    string
+		ok       bool
+		expected time.Duration
+	}{
+		{"", false, 0},
+		{"3", true, 3 * time.Second},
+		{"-1", false, 0},
+		{"soon", false, 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", true, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			resp.Header.Set("Retry-After", tc.value)
+
+			d, ok := retryAfter(resp)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, d)
+		})
+	}
+}
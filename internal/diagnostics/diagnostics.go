@@ -0,0 +1,220 @@
+// Package diagnostics turns errors into diagnostics, so the SDKv2 and the plugin framework halves of the provider
+// report errors from the Rockset API the same way.
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdkdiag "github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+)
+
+// SQL is the SQL sent in the API call which failed, and the attribute it came from. When Rockset rejects the SQL,
+// the diagnostic is attached to the attribute, and shows the line of the SQL with the error.
+type SQL struct {
+	Query string
+	// Path is the path of the attribute, as attribute names and list indexes, e.g. "sql", 0, "query".
+	Path []interface{}
+}
+
+// Diagnostic is a diagnostic which isn't specific to either half of the provider.
+type Diagnostic struct {
+	Summary string
+	Detail  string
+	// Path is the attribute the error is about, or nil if it isn't about an attribute.
+	Path []interface{}
+}
+
+// FromError creates a Diagnostic for err, and if err is a Rockset error, the detail contains everything Rockset
+// returned about the error. If the error has a line in the SQL, the Diagnostic points at it.
+func FromError(err error, sql ...SQL) Diagnostic {
+	d := Diagnostic{Summary: err.Error()}
+
+	var re rockerr.Error
+	if !errors.As(err, &re) {
+		return d
+	}
+
+	var msgs []string
+	if t, ok := re.GetTypeOk(); ok {
+		msgs = append(msgs, fmt.Sprintf("Error Type: %s", *t))
+	}
+	if re.StatusCode != 0 {
+		msgs = append(msgs, fmt.Sprintf("HTTP status code (%d) %s", re.StatusCode, http.StatusText(re.StatusCode)))
+	}
+	if re.GetTraceId() != "" {
+		msgs = append(msgs, fmt.Sprintf("Trace ID: %s", re.GetTraceId()))
+	}
+	if re.GetErrorId() != "" {
+		msgs = append(msgs, fmt.Sprintf("Error ID: %s", re.GetErrorId()))
+	}
+	if re.GetQueryId() != "" {
+		msgs = append(msgs, fmt.Sprintf("Query ID: %s", re.GetQueryId()))
+	}
+	if re.HasLine() {
+		msgs = append(msgs, fmt.Sprintf("Line: %d", re.GetLine()))
+	}
+	if re.HasColumn() {
+		msgs = append(msgs, fmt.Sprintf("Column: %d", re.GetColumn()))
+	}
+	d.Detail = re.GetMessage() + ": " + strings.Join(msgs, ", ")
+
+	// only errors with a line are about the SQL, as e.g. a missing workspace also fails the API call
+	if len(sql) > 0 && re.HasLine() {
+		d.Path = sql[0].Path
+		if excerpt := Excerpt(sql[0].Query, int(re.GetLine()), int(re.GetColumn())); excerpt != "" {
+			d.Detail += "\n\n" + excerpt
+		}
+	}
+
+	return d
+}
+
+// Excerpt returns the line of the query, with a caret below the column, or an empty string if the query doesn't
+// have the line. Lines and columns start at 1, and if the column is unknown no caret is shown.
+func Excerpt(query string, line, column int) string {
+	lines := strings.Split(query, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	text := strings.TrimRight(lines[line-1], "\r")
+	number := fmt.Sprintf("%d", line)
+	gutter := strings.Repeat(" ", len(number))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s | %s", number, text)
+	if column < 1 {
+		return sb.String()
+	}
+
+	// keep the tabs before the column, so the caret lines up with the text
+	var indent strings.Builder
+	for i, r := range []rune(text) {
+		if i >= column-1 {
+			break
+		}
+		if r == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+	fmt.Fprintf(&sb, "\n%s | %s^", gutter, indent.String())
+
+	return sb.String()
+}
+
+// SDK returns err as SDKv2 diagnostics, or nil if err is nil.
+func SDK(err error, sql ...SQL) sdkdiag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+
+	d := FromError(err, sql...)
+
+	return sdkdiag.Diagnostics{{
+		Severity:      sdkdiag.Error,
+		Summary:       d.Summary,
+		Detail:        d.Detail,
+		AttributePath: d.ctyPath(),
+	}}
+}
+
+// Proto returns err as a protocol diagnostic, for errors which are reported outside either SDK, e.g. while planning.
+func Proto(err error, sql ...SQL) *tfprotov5.Diagnostic {
+	return FromError(err, sql...).Proto(tfprotov5.DiagnosticSeverityError)
+}
+
+// Proto returns d as a protocol diagnostic with the severity.
+func (d Diagnostic) Proto(severity tfprotov5.DiagnosticSeverity) *tfprotov5.Diagnostic {
+	return &tfprotov5.Diagnostic{
+		Severity:  severity,
+		Summary:   d.Summary,
+		Detail:    d.Detail,
+		Attribute: d.protoPath(),
+	}
+}
+
+// AddError adds err to the plugin framework diagnostics, attached to the attribute if the error is about it.
+func AddError(diags *fwdiag.Diagnostics, err error, sql ...SQL) {
+	if err == nil {
+		return
+	}
+
+	d := FromError(err, sql...)
+	if d.Path == nil {
+		diags.AddError(d.Summary, d.Detail)
+		return
+	}
+
+	diags.AddAttributeError(d.frameworkPath(), d.Summary, d.Detail)
+}
+
+func (d Diagnostic) ctyPath() cty.Path {
+	if d.Path == nil {
+		return nil
+	}
+
+	p := make(cty.Path, 0, len(d.Path))
+	for _, step := range d.Path {
+		switch s := step.(type) {
+		case string:
+			p = p.GetAttr(s)
+		case int:
+			p = p.Index(cty.NumberIntVal(int64(s)))
+		default:
+			panic(fmt.Sprintf("unsupported path step %T", step))
+		}
+	}
+
+	return p
+}
+
+func (d Diagnostic) protoPath() *tftypes.AttributePath {
+	if d.Path == nil {
+		return nil
+	}
+
+	p := tftypes.NewAttributePath()
+	for _, step := range d.Path {
+		switch s := step.(type) {
+		case string:
+			p = p.WithAttributeName(s)
+		case int:
+			p = p.WithElementKeyInt(s)
+		default:
+			panic(fmt.Sprintf("unsupported path step %T", step))
+		}
+	}
+
+	return p
+}
+
+func (d Diagnostic) frameworkPath() path.Path {
+	var p path.Path
+	for i, step := range d.Path {
+		switch s := step.(type) {
+		case string:
+			if i == 0 {
+				p = path.Root(s)
+			} else {
+				p = p.AtName(s)
+			}
+		case int:
+			p = p.AtListIndex(s)
+		default:
+			panic(fmt.Sprintf("unsupported path step %T", step))
+		}
+	}
+
+	return p
+}
@@ -0,0 +1,151 @@
+package diagnostics
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sqlError(line, column int32) error {
+	err := rockerr.NewWithStatusCode(errors.New("apierr"), &http.Response{StatusCode: http.StatusBadRequest})
+
+	var re rockerr.Error
+	if !errors.As(err, &re) {
+		panic("not a rockset error")
+	}
+	re.ErrorModel = &openapi.ErrorModel{
+		Message: openapi.PtrString("Column 'nme' not found"),
+		Type:    openapi.PtrString("INVALIDINPUT"),
+		TraceId: openapi.PtrString("trace"),
+	}
+	if line != 0 {
+		re.Line = openapi.PtrInt32(line)
+		re.Column = openapi.PtrInt32(column)
+	}
+
+	return re
+}
+
+const query = "SELECT\n\tnme\nFROM commons._events"
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		sql    []SQL
+		detail string
+		path   []interface{}
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("plain error"),
+			sql:  []SQL{{Query: query, Path: []interface{}{"query"}}},
+		},
+		{
+			name: "rockset error without sql",
+			err:  sqlError(2, 2),
+			detail: "Column 'nme' not found: Error Type: INVALIDINPUT, HTTP status code (400) Bad Request, " +
+				"Trace ID: trace, Line: 2, Column: 2",
+		},
+		{
+			name: "rockset error with sql",
+			err:  sqlError(2, 2),
+			sql:  []SQL{{Query: query, Path: []interface{}{"sql", 0, "query"}}},
+			detail: "Column 'nme' not found: Error Type: INVALIDINPUT, HTTP status code (400) Bad Request, " +
+				"Trace ID: trace, Line: 2, Column: 2\n\n2 | \tnme\n  | \t^",
+			path: []interface{}{"sql", 0, "query"},
+		},
+		{
+			name:   "rockset error without line",
+			err:    sqlError(0, 0),
+			sql:    []SQL{{Query: query, Path: []interface{}{"query"}}},
+			detail: "Column 'nme' not found: Error Type: INVALIDINPUT, HTTP status code (400) Bad Request, Trace ID: trace",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			d := FromError(tst.err, tst.sql...)
+			assert.Equal(t, tst.err.Error(), d.Summary)
+			assert.Equal(t, tst.detail, d.Detail)
+			assert.Equal(t, tst.path, d.Path)
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   int
+		column int
+		want   string
+	}{
+		{"first line", 1, 1, "1 | SELECT\n  | ^"},
+		{"tab before column", 2, 3, "2 | \tnme\n  | \t ^"},
+		{"column past the end", 3, 40, "3 | FROM commons._events\n  |                     ^"},
+		{"unknown column", 3, 0, "3 | FROM commons._events"},
+		{"unknown line", 4, 1, ""},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert.Equal(t, tst.want, Excerpt(query, tst.line, tst.column))
+		})
+	}
+}
+
+func TestSDK(t *testing.T) {
+	assert.Nil(t, SDK(nil))
+
+	diags := SDK(sqlError(2, 2), SQL{Query: query, Path: []interface{}{"sql", 0, "query"}})
+	require.Len(t, diags, 1)
+	assert.Equal(t, cty.GetAttrPath("sql").IndexInt(0).GetAttr("query"), diags[0].AttributePath)
+}
+
+func TestProto(t *testing.T) {
+	d := Proto(sqlError(2, 2), SQL{Query: query, Path: []interface{}{"sql", 0, "query"}})
+	assert.Equal(t, tfprotov5.DiagnosticSeverityError, d.Severity)
+	assert.Equal(t, tftypes.NewAttributePath().WithAttributeName("sql").WithElementKeyInt(0).WithAttributeName("query"),
+		d.Attribute)
+
+	d = Proto(errors.New("plain error"))
+	assert.Equal(t, "plain error", d.Summary)
+	assert.Nil(t, d.Attribute)
+}
+
+func TestDiagnostic_Proto(t *testing.T) {
+	d := Diagnostic{Summary: "unused", Path: []interface{}{"sql", 0, "default_parameter"}}.
+		Proto(tfprotov5.DiagnosticSeverityWarning)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, d.Severity)
+	assert.Equal(t, "unused", d.Summary)
+	assert.Equal(t, tftypes.NewAttributePath().WithAttributeName("sql").WithElementKeyInt(0).
+		WithAttributeName("default_parameter"), d.Attribute)
+}
+
+func TestAddError(t *testing.T) {
+	var diags fwdiag.Diagnostics
+	AddError(&diags, nil)
+	assert.False(t, diags.HasError())
+
+	AddError(&diags, sqlError(2, 2), SQL{Query: query, Path: []interface{}{"sql", 0, "query"}})
+	require.Len(t, diags, 1)
+	withPath, ok := diags[0].(fwdiag.DiagnosticWithPath)
+	require.True(t, ok)
+	assert.Equal(t, path.Root("sql").AtListIndex(0).AtName("query"), withPath.Path())
+
+	AddError(&diags, errors.New("plain error"))
+	require.Len(t, diags, 2)
+	_, ok = diags[1].(fwdiag.DiagnosticWithPath)
+	assert.False(t, ok)
+	assert.Equal(t, "plain error", diags[1].Summary())
+}
@@ -9,7 +9,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+	"github.com/rockset/terraform-provider-rockset/internal/tracing"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -21,7 +25,7 @@ func NewCollectionSourceDataSource() datasource.DataSource {
 
 // CollectionSourceDataSource defines the data source implementation.
 type CollectionSourceDataSource struct {
-	client *rockset.RockClient
+	data ProviderData
 }
 
 // CollectionSourceDataSourceModel describes the data source data model.
@@ -118,17 +122,17 @@ func (d *CollectionSourceDataSource) Configure(ctx context.Context, req datasour
 		return
 	}
 
-	client, ok := req.ProviderData.(*rockset.RockClient)
+	data, ok := req.ProviderData.(ProviderData)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = client
+	d.data = data
 }
 
 func (d *CollectionSourceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -145,11 +149,25 @@ func (d *CollectionSourceDataSource) Read(ctx context.Context, req datasource.Re
 	collection := data.Collection.ValueString()
 	id := data.Id.ValueString()
 
-	request := d.client.SourcesApi.GetSource(ctx, workspace, collection, id)
-	response, _, err := request.Execute()
+	rc := d.data.Client(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, span := tracing.StartOperation(ctx, "rockset_collection_source", "read", id)
+	request := rc.SourcesApi.GetSource(ctx, workspace, collection, id)
+	response, httpResp, err := request.Execute()
+	tracing.EndOperation(span, id, err)
 	if err != nil {
-		resp.Diagnostics.AddError("GetSource error",
-			fmt.Sprintf("Unable to get collection source, got error: %s", err))
+		err = rockerr.NewWithStatusCode(err, httpResp)
+		if client.IsNotFound(err) {
+			// a data source can't be removed from the state like a resource, so it has to fail
+			resp.Diagnostics.AddError("Collection source not found",
+				fmt.Sprintf("Source %s of collection %s.%s doesn't exist.", id, workspace, collection))
+			return
+		}
+
+		diagnostics.AddError(&resp.Diagnostics, err)
 		return
 	}
 
@@ -0,0 +1,637 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+	"github.com/rockset/terraform-provider-rockset/internal/tracing"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var (
+	_ resource.Resource                   = &CollectionSourceResource{}
+	_ resource.ResourceWithImportState    = &CollectionSourceResource{}
+	_ resource.ResourceWithValidateConfig = &CollectionSourceResource{}
+)
+
+const collectionSourceType = "rockset_collection_source"
+
+func NewCollectionSourceResource() resource.Resource {
+	return &CollectionSourceResource{}
+}
+
+// CollectionSourceResource manages a single source of a collection, so sources can be added to and removed from
+// a collection without replacing it.
+type CollectionSourceResource struct {
+	data ProviderData
+}
+
+// CollectionSourceResourceModel describes the resource data model. Exactly one of the source blocks is set.
+type CollectionSourceResourceModel struct {
+	Workspace       types.String `tfsdk:"workspace"`
+	Collection      types.String `tfsdk:"collection"`
+	Id              types.String `tfsdk:"id"`
+	IntegrationName types.String `tfsdk:"integration_name"`
+	State           types.String `tfsdk:"state"`
+	Suspended       types.Bool   `tfsdk:"suspended"`
+	ResumeAfter     types.String `tfsdk:"resume_after"`
+	ResumeAt        types.String `tfsdk:"resume_at"`
+
+	S3       *S3SourceModel       `tfsdk:"s3"`
+	GCS      *GCSSourceModel      `tfsdk:"gcs"`
+	Kafka    *KafkaSourceModel    `tfsdk:"kafka"`
+	Kinesis  *KinesisSourceModel  `tfsdk:"kinesis"`
+	DynamoDB *DynamoDBSourceModel `tfsdk:"dynamodb"`
+	MongoDB  *MongoDBSourceModel  `tfsdk:"mongodb"`
+}
+
+type S3SourceModel struct {
+	Bucket  types.String `tfsdk:"bucket"`
+	Prefix  types.String `tfsdk:"prefix"`
+	Pattern types.String `tfsdk:"pattern"`
+	Region  types.String `tfsdk:"region"`
+}
+
+type GCSSourceModel struct {
+	Bucket  types.String `tfsdk:"bucket"`
+	Prefix  types.String `tfsdk:"prefix"`
+	Pattern types.String `tfsdk:"pattern"`
+}
+
+type KafkaSourceModel struct {
+	TopicName         types.String `tfsdk:"topic_name"`
+	ConsumerGroupId   types.String `tfsdk:"consumer_group_id"`
+	OffsetResetPolicy types.String `tfsdk:"offset_reset_policy"`
+	UseV3             types.Bool   `tfsdk:"use_v3"`
+}
+
+type KinesisSourceModel struct {
+	StreamName        types.String   `tfsdk:"stream_name"`
+	AwsRegion         types.String   `tfsdk:"aws_region"`
+	DmsPrimaryKey     []types.String `tfsdk:"dms_primary_key"`
+	OffsetResetPolicy types.String   `tfsdk:"offset_reset_policy"`
+}
+
+type DynamoDBSourceModel struct {
+	TableName  types.String `tfsdk:"table_name"`
+	AwsRegion  types.String `tfsdk:"aws_region"`
+	Rcu        types.Int64  `tfsdk:"rcu"`
+	UseScanApi types.Bool   `tfsdk:"use_scan_api"`
+}
+
+type MongoDBSourceModel struct {
+	DatabaseName         types.String `tfsdk:"database_name"`
+	CollectionName       types.String `tfsdk:"collection_name"`
+	RetrieveFullDocument types.Bool   `tfsdk:"retrieve_full_document"`
+}
+
+func (r *CollectionSourceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_collection_source"
+}
+
+// optionalString is an attribute which Rockset defaults when it isn't set.
+func optionalString(description string) schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+	}
+}
+
+func optionalBool(description string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+	}
+}
+
+// sourceBlock is the block of a kind of source. Sources can't be updated, so changing it replaces the source.
+func sourceBlock(description string, attributes map[string]schema.Attribute) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		MarkdownDescription: description,
+		Attributes:          attributes,
+		PlanModifiers:       []planmodifier.Object{objectplanmodifier.RequiresReplace()},
+	}
+}
+
+func (r *CollectionSourceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a source of a collection, so sources can be added to and removed from an " +
+			"existing collection without recreating it. Exactly one of the source blocks must be set, " +
+			"and changing the source replaces it.\n\n" +
+			"~> Only use it with collections which don't have `source` blocks, such as `rockset_collection`, " +
+			"as the typed collection resources see sources added by it as changes, which replace the collection.",
+		Attributes: map[string]schema.Attribute{
+			"workspace": schema.StringAttribute{
+				MarkdownDescription: "Workspace name.",
+				Required:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"collection": schema.StringAttribute{
+				MarkdownDescription: "Name of the collection to add the source to.",
+				Required:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Source identifier.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"integration_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the integration to use.",
+				Optional:            true,
+				PlanModifiers:       requiresReplace,
+			},
+			"state": schema.StringAttribute{
+				MarkdownDescription: "State of the source.",
+				Computed:            true,
+			},
+			"suspended": schema.BoolAttribute{
+				MarkdownDescription: "Suspend ingesting from the source. Rockset resumes it at `resume_at`, which " +
+					"isn't reported as a change, so it isn't suspended again until `resume_after` changes. A source " +
+					"which is resumed outside of Terraform before `resume_at` is suspended again by the next apply.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"resume_after": schema.StringAttribute{
+				MarkdownDescription: "How long the source is suspended for, e.g. `30m` or `12h`. " +
+					"Rockset resumes it after an hour by default.",
+				Optional: true,
+			},
+			"resume_at": schema.StringAttribute{
+				MarkdownDescription: "When Rockset resumes the suspended source, as an RFC 3339 timestamp.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"s3": sourceBlock("Ingest from an S3 bucket.", map[string]schema.Attribute{
+				"bucket": schema.StringAttribute{
+					MarkdownDescription: "S3 bucket containing the data.",
+					Required:            true,
+				},
+				"prefix": schema.StringAttribute{
+					MarkdownDescription: "Prefix that selects the keys to ingest.",
+					Optional:            true,
+				},
+				"pattern": schema.StringAttribute{
+					MarkdownDescription: "Glob-style pattern that selects the keys to ingest. " +
+						"Only either prefix or pattern can be set.",
+					Optional: true,
+				},
+				"region": optionalString("AWS region of the bucket."),
+			}),
+			"gcs": sourceBlock("Ingest from a GCS bucket.", map[string]schema.Attribute{
+				"bucket": schema.StringAttribute{
+					MarkdownDescription: "GCS bucket containing the data.",
+					Required:            true,
+				},
+				"prefix": schema.StringAttribute{
+					MarkdownDescription: "Prefix that selects the objects to ingest.",
+					Optional:            true,
+				},
+				"pattern": schema.StringAttribute{
+					MarkdownDescription: "Glob-style pattern that selects the objects to ingest. " +
+						"Only either prefix or pattern can be set.",
+					Optional: true,
+				},
+			}),
+			"kafka": sourceBlock("Ingest from a Kafka topic.", map[string]schema.Attribute{
+				"topic_name": schema.StringAttribute{
+					MarkdownDescription: "Kafka topic to ingest.",
+					Required:            true,
+				},
+				"consumer_group_id": optionalString("Kafka consumer group ID."),
+				"offset_reset_policy": optionalString("Where to start ingesting the topic when there is no " +
+					"committed offset, `EARLIEST` or `LATEST`."),
+				"use_v3": optionalBool("Use a v3 Kafka integration."),
+			}),
+			"kinesis": sourceBlock("Ingest from a Kinesis stream.", map[string]schema.Attribute{
+				"stream_name": schema.StringAttribute{
+					MarkdownDescription: "Name of the Kinesis stream.",
+					Required:            true,
+				},
+				"aws_region": optionalString("AWS region of the stream."),
+				"dms_primary_key": schema.ListAttribute{
+					MarkdownDescription: "Fields which are the primary key of a DMS stream.",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+				"offset_reset_policy": optionalString("Where to start ingesting the stream, `EARLIEST` or `LATEST`."),
+			}),
+			"dynamodb": sourceBlock("Ingest from a DynamoDB table.", map[string]schema.Attribute{
+				"table_name": schema.StringAttribute{
+					MarkdownDescription: "Name of the DynamoDB table.",
+					Required:            true,
+				},
+				"aws_region": optionalString("AWS region of the table."),
+				"rcu": schema.Int64Attribute{
+					MarkdownDescription: "Maximum read capacity units to use for the initial scan.",
+					Optional:            true,
+					Computed:            true,
+					PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+				},
+				"use_scan_api": optionalBool("Use the DynamoDB Scan API for the initial scan."),
+			}),
+			"mongodb": sourceBlock("Ingest from a MongoDB collection.", map[string]schema.Attribute{
+				"database_name": schema.StringAttribute{
+					MarkdownDescription: "MongoDB database containing the collection.",
+					Required:            true,
+				},
+				"collection_name": schema.StringAttribute{
+					MarkdownDescription: "MongoDB collection name.",
+					Required:            true,
+				},
+				"retrieve_full_document": optionalBool("Get the full document from the change stream, " +
+					"which is needed for transformations of multiple fields, but increases the load on MongoDB."),
+			}),
+		},
+	}
+}
+
+func (r *CollectionSourceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.data = data
+}
+
+// sourceBlocks are the names of the source blocks, of which exactly one must be set.
+var sourceBlocks = []string{"s3", "gcs", "kafka", "kinesis", "dynamodb", "mongodb"}
+
+func (r *CollectionSourceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var resumeAfter types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("resume_after"), &resumeAfter)...)
+	if !resumeAfter.IsNull() && !resumeAfter.IsUnknown() && !client.ResumeAfterRe.MatchString(resumeAfter.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("resume_after"), "Invalid resume_after",
+			fmt.Sprintf("resume_after must be a number of seconds, minutes or hours, e.g. 30m or 12h, but got %q.",
+				resumeAfter.ValueString()))
+	}
+
+	var set []string
+	for _, block := range sourceBlocks {
+		var v types.Object
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(block), &v)...)
+		if !v.IsNull() {
+			set = append(set, block)
+		}
+	}
+	if resp.Diagnostics.HasError() || len(set) == 1 {
+		return
+	}
+
+	resp.Diagnostics.AddError("Invalid source",
+		fmt.Sprintf("Exactly one of the %s blocks must be set, but got %d.", strings.Join(sourceBlocks, ", "),
+			len(set)))
+}
+
+func (r *CollectionSourceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !r.data.CheckReadOnly(&resp.Diagnostics, "create", collectionSourceType) {
+		return
+	}
+
+	var data CollectionSourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rc := r.data.Client(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := data.Workspace.ValueString()
+	collection := data.Collection.ValueString()
+
+	ctx, span := tracing.StartOperation(ctx, collectionSourceType, "create", workspace+"."+collection)
+	response, httpResp, err := rc.SourcesApi.CreateSource(ctx, workspace, collection).Body(data.source()).Execute()
+	tracing.EndOperation(span, workspace+"."+collection, err)
+	if err != nil {
+		diagnostics.AddError(&resp.Diagnostics, rockerr.NewWithStatusCode(err, httpResp))
+		return
+	}
+
+	suspended := data.Suspended.ValueBool()
+	data.update(response.GetData())
+	tflog.Trace(ctx, "created rockset collection source", map[string]interface{}{
+		"workspace":  workspace,
+		"collection": collection,
+		"id":         data.Id.ValueString(),
+	})
+
+	// the source is created before it is suspended, so the state is saved even when suspending it fails
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() || !suspended {
+		return
+	}
+
+	r.setSuspended(ctx, &data, true, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CollectionSourceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CollectionSourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rc := r.data.Client(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := data.Workspace.ValueString()
+	collection := data.Collection.ValueString()
+	id := data.Id.ValueString()
+
+	ctx, span := tracing.StartOperation(ctx, collectionSourceType, "read", id)
+	response, httpResp, err := rc.SourcesApi.GetSource(ctx, workspace, collection, id).Execute()
+	tracing.EndOperation(span, id, err)
+	if err != nil {
+		err = rockerr.NewWithStatusCode(err, httpResp)
+		if client.IsNotFound(err) {
+			tflog.Warn(ctx, "collection source not found, removing it from the state",
+				map[string]interface{}{"workspace": workspace, "collection": collection, "id": id})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		diagnostics.AddError(&resp.Diagnostics, err)
+		return
+	}
+
+	prior := data
+	data.update(response.GetData())
+	// a source which Rockset resumed at resume_at still is suspended as configured, and only is drift when it is
+	// resumed before then
+	if prior.Suspended.ValueBool() && !data.Suspended.ValueBool() &&
+		client.ResumedAutomatically(prior.ResumeAt.ValueString(), time.Now()) {
+		data.Suspended = prior.Suspended
+		data.ResumeAt = prior.ResumeAt
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update suspends or resumes the source, as every other attribute which can be configured replaces the source
+// when it changes.
+func (r *CollectionSourceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !r.data.CheckReadOnly(&resp.Diagnostics, "update", collectionSourceType) {
+		return
+	}
+
+	var data, current CollectionSourceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &current)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// suspending a suspended source again restarts resume_after, so it is only done when it has changed, and
+	// a source which Rockset has resumed already doesn't need to be resumed
+	suspended := data.Suspended.ValueBool()
+	changed := suspended != current.Suspended.ValueBool() ||
+		(suspended && !data.ResumeAfter.Equal(current.ResumeAfter))
+	if changed && !suspended && client.ResumedAutomatically(current.ResumeAt.ValueString(), time.Now()) {
+		changed = false
+		current.ResumeAt = types.StringNull()
+	}
+
+	if changed {
+		r.setSuspended(ctx, &data, suspended, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	} else {
+		data.State = current.State
+		data.ResumeAt = current.ResumeAt
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// setSuspended suspends or resumes the source, and updates the model from the source once its state has changed.
+func (r *CollectionSourceResource) setSuspended(ctx context.Context, data *CollectionSourceResourceModel, suspended bool,
+	diags *diag.Diagnostics) {
+	rc := r.data.Client(ctx, diags)
+	if diags.HasError() {
+		return
+	}
+
+	workspace := data.Workspace.ValueString()
+	collection := data.Collection.ValueString()
+	id := data.Id.ValueString()
+
+	ctx, span := tracing.StartOperation(ctx, collectionSourceType, "update", id)
+	src, err := client.SetSourceSuspended(ctx, rc, workspace, collection, id, suspended, data.ResumeAfter.ValueString())
+	tracing.EndOperation(span, id, err)
+	if err != nil {
+		diagnostics.AddError(diags, err)
+		return
+	}
+
+	data.update(src)
+}
+
+func (r *CollectionSourceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if !r.data.CheckReadOnly(&resp.Diagnostics, "delete", collectionSourceType) {
+		return
+	}
+
+	var data CollectionSourceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rc := r.data.Client(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspace := data.Workspace.ValueString()
+	collection := data.Collection.ValueString()
+	id := data.Id.ValueString()
+
+	ctx, span := tracing.StartOperation(ctx, collectionSourceType, "delete", id)
+	_, httpResp, err := rc.SourcesApi.DeleteSource(ctx, workspace, collection, id).Execute()
+	tracing.EndOperation(span, id, err)
+	if err != nil {
+		err = rockerr.NewWithStatusCode(err, httpResp)
+		if client.IsNotFound(err) {
+			return
+		}
+
+		diagnostics.AddError(&resp.Diagnostics, err)
+	}
+}
+
+// ImportState imports a source using an ID of the form workspace.collection.id.
+func (r *CollectionSourceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parts := strings.Split(req.ID, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError("Invalid import ID",
+			fmt.Sprintf("The import ID must be workspace.collection.id, but got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("collection"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), parts[2])...)
+}
+
+// source returns the source to create.
+func (m CollectionSourceResourceModel) source() openapi.Source {
+	src := openapi.Source{IntegrationName: m.IntegrationName.ValueStringPointer()}
+
+	switch {
+	case m.S3 != nil:
+		src.S3 = &openapi.SourceS3{
+			Bucket:  m.S3.Bucket.ValueString(),
+			Prefix:  m.S3.Prefix.ValueStringPointer(),
+			Pattern: m.S3.Pattern.ValueStringPointer(),
+			Region:  m.S3.Region.ValueStringPointer(),
+		}
+	case m.GCS != nil:
+		src.Gcs = &openapi.SourceGcs{
+			Bucket:  m.GCS.Bucket.ValueStringPointer(),
+			Prefix:  m.GCS.Prefix.ValueStringPointer(),
+			Pattern: m.GCS.Pattern.ValueStringPointer(),
+		}
+	case m.Kafka != nil:
+		src.Kafka = &openapi.SourceKafka{
+			KafkaTopicName:    m.Kafka.TopicName.ValueStringPointer(),
+			ConsumerGroupId:   m.Kafka.ConsumerGroupId.ValueStringPointer(),
+			OffsetResetPolicy: m.Kafka.OffsetResetPolicy.ValueStringPointer(),
+			UseV3:             m.Kafka.UseV3.ValueBoolPointer(),
+		}
+	case m.Kinesis != nil:
+		src.Kinesis = &openapi.SourceKinesis{
+			StreamName:        m.Kinesis.StreamName.ValueString(),
+			AwsRegion:         m.Kinesis.AwsRegion.ValueStringPointer(),
+			OffsetResetPolicy: m.Kinesis.OffsetResetPolicy.ValueStringPointer(),
+		}
+		for _, key := range m.Kinesis.DmsPrimaryKey {
+			src.Kinesis.DmsPrimaryKey = append(src.Kinesis.DmsPrimaryKey, key.ValueString())
+		}
+	case m.DynamoDB != nil:
+		src.Dynamodb = &openapi.SourceDynamoDb{
+			TableName:  m.DynamoDB.TableName.ValueString(),
+			AwsRegion:  m.DynamoDB.AwsRegion.ValueStringPointer(),
+			Rcu:        m.DynamoDB.Rcu.ValueInt64Pointer(),
+			UseScanApi: m.DynamoDB.UseScanApi.ValueBoolPointer(),
+		}
+	case m.MongoDB != nil:
+		src.Mongodb = &openapi.SourceMongoDb{
+			DatabaseName:         m.MongoDB.DatabaseName.ValueString(),
+			CollectionName:       m.MongoDB.CollectionName.ValueString(),
+			RetrieveFullDocument: m.MongoDB.RetrieveFullDocument.ValueBoolPointer(),
+		}
+	}
+
+	return src
+}
+
+// update sets the model from the source returned by Rockset.
+func (m *CollectionSourceResourceModel) update(src openapi.Source) {
+	m.Id = types.StringValue(src.GetId())
+	m.IntegrationName = optionalStringValue(src.IntegrationName)
+	m.State = types.StringValue(src.Status.GetState())
+	m.Suspended = types.BoolValue(client.IsSourceSuspended(src))
+	m.ResumeAt = types.StringNull()
+	if client.IsSourceSuspended(src) {
+		m.ResumeAt = optionalStringValue(src.ResumeAt)
+	}
+
+	m.S3, m.GCS, m.Kafka, m.Kinesis, m.DynamoDB, m.MongoDB = nil, nil, nil, nil, nil, nil
+	switch {
+	case src.S3 != nil:
+		m.S3 = &S3SourceModel{
+			Bucket:  types.StringValue(src.S3.Bucket),
+			Prefix:  optionalStringValue(src.S3.Prefix),
+			Pattern: optionalStringValue(src.S3.Pattern),
+			Region:  types.StringValue(src.S3.GetRegion()),
+		}
+	case src.Gcs != nil:
+		m.GCS = &GCSSourceModel{
+			Bucket:  types.StringValue(src.Gcs.GetBucket()),
+			Prefix:  optionalStringValue(src.Gcs.Prefix),
+			Pattern: optionalStringValue(src.Gcs.Pattern),
+		}
+	case src.Kafka != nil:
+		m.Kafka = &KafkaSourceModel{
+			TopicName:         types.StringValue(src.Kafka.GetKafkaTopicName()),
+			ConsumerGroupId:   types.StringValue(src.Kafka.GetConsumerGroupId()),
+			OffsetResetPolicy: types.StringValue(src.Kafka.GetOffsetResetPolicy()),
+			UseV3:             types.BoolValue(src.Kafka.GetUseV3()),
+		}
+	case src.Kinesis != nil:
+		m.Kinesis = &KinesisSourceModel{
+			StreamName:        types.StringValue(src.Kinesis.StreamName),
+			AwsRegion:         types.StringValue(src.Kinesis.GetAwsRegion()),
+			OffsetResetPolicy: types.StringValue(src.Kinesis.GetOffsetResetPolicy()),
+		}
+		for _, key := range src.Kinesis.DmsPrimaryKey {
+			m.Kinesis.DmsPrimaryKey = append(m.Kinesis.DmsPrimaryKey, types.StringValue(key))
+		}
+	case src.Dynamodb != nil:
+		m.DynamoDB = &DynamoDBSourceModel{
+			TableName:  types.StringValue(src.Dynamodb.TableName),
+			AwsRegion:  types.StringValue(src.Dynamodb.GetAwsRegion()),
+			Rcu:        types.Int64Value(src.Dynamodb.GetRcu()),
+			UseScanApi: types.BoolValue(src.Dynamodb.GetUseScanApi()),
+		}
+	case src.Mongodb != nil:
+		m.MongoDB = &MongoDBSourceModel{
+			DatabaseName:         types.StringValue(src.Mongodb.DatabaseName),
+			CollectionName:       types.StringValue(src.Mongodb.CollectionName),
+			RetrieveFullDocument: types.BoolValue(src.Mongodb.GetRetrieveFullDocument()),
+		}
+	}
+}
+
+// optionalStringValue returns a null string for an attribute which isn't set, which Rockset can return as empty.
+func optionalStringValue(s *string) types.String {
+	if s == nil || *s == "" {
+		return types.StringNull()
+	}
+
+	return types.StringValue(*s)
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestCollectionSourceResource(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	base := client.BaseTransport
+	client.BaseTransport = srv.Client().Transport
+	defer func() { client.BaseTransport = base }()
+
+	r := &CollectionSourceResource{data: ProviderData{
+		lazy: client.NewLazy(client.Config{APIKey: rocksettest.APIKey, APIServer: srv.URL}),
+	}}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+	null := tftypes.NewValue(s.Type().TerraformType(ctx), nil)
+
+	var diags diag.Diagnostics
+	rc := r.data.Client(ctx, &diags)
+	require.False(t, diags.HasError(), diags)
+	_, err := rc.CreateCollection(ctx, "commons", "orders")
+	require.NoError(t, err)
+
+	plan := tfsdk.Plan{Schema: s, Raw: null}
+	require.False(t, plan.Set(ctx, &CollectionSourceResourceModel{
+		Workspace:       types.StringValue("commons"),
+		Collection:      types.StringValue("orders"),
+		Id:              types.StringUnknown(),
+		IntegrationName: types.StringNull(),
+		State:           types.StringUnknown(),
+		ResumeAt:        types.StringUnknown(),
+		Kinesis: &KinesisSourceModel{
+			StreamName:        types.StringValue("orders"),
+			AwsRegion:         types.StringValue("us-west-2"),
+			DmsPrimaryKey:     []types.String{types.StringValue("id")},
+			OffsetResetPolicy: types.StringUnknown(),
+		},
+	}).HasError())
+
+	createResp := resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: null}}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &createResp)
+	require.False(t, createResp.Diagnostics.HasError(), createResp.Diagnostics)
+
+	var created CollectionSourceResourceModel
+	require.False(t, createResp.State.Get(ctx, &created).HasError())
+	assert.NotEmpty(t, created.Id.ValueString())
+	assert.Equal(t, "INITIALIZING", created.State.ValueString())
+	require.NotNil(t, created.Kinesis)
+	assert.Equal(t, "us-west-2", created.Kinesis.AwsRegion.ValueString())
+	assert.Equal(t, []types.String{types.StringValue("id")}, created.Kinesis.DmsPrimaryKey)
+
+	// importing sets the identifiers, from which Read sets the rest of the state
+	importResp := resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: null}}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "commons.orders." + created.Id.ValueString()}, &importResp)
+	require.False(t, importResp.Diagnostics.HasError(), importResp.Diagnostics)
+
+	readResp := resource.ReadResponse{State: importResp.State}
+	r.Read(ctx, resource.ReadRequest{State: importResp.State}, &readResp)
+	require.False(t, readResp.Diagnostics.HasError(), readResp.Diagnostics)
+
+	var imported CollectionSourceResourceModel
+	require.False(t, readResp.State.Get(ctx, &imported).HasError())
+	assert.Equal(t, created, imported)
+	assert.False(t, created.Suspended.ValueBool())
+
+	// suspending the source updates it in place
+	planned := created
+	planned.Suspended = types.BoolValue(true)
+	planned.ResumeAfter = types.StringValue("30m")
+	planned.State = types.StringUnknown()
+	planned.ResumeAt = types.StringUnknown()
+	plan = tfsdk.Plan{Schema: s, Raw: null}
+	require.False(t, plan.Set(ctx, &planned).HasError())
+
+	updateResp := resource.UpdateResponse{State: createResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: plan, State: createResp.State}, &updateResp)
+	require.False(t, updateResp.Diagnostics.HasError(), updateResp.Diagnostics)
+
+	var updated CollectionSourceResourceModel
+	require.False(t, updateResp.State.Get(ctx, &updated).HasError())
+	assert.True(t, updated.Suspended.ValueBool())
+	assert.Equal(t, client.SourceSuspendedState, updated.State.ValueString())
+	assert.Equal(t, "30m", updated.ResumeAfter.ValueString())
+	assert.NotEmpty(t, updated.ResumeAt.ValueString())
+
+	// resuming it outside of Terraform before resume_at is drift
+	_, err = client.SetSourceSuspended(ctx, rc, "commons", "orders", created.Id.ValueString(), false, "")
+	require.NoError(t, err)
+	readResp = resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &readResp)
+	require.False(t, readResp.Diagnostics.HasError(), readResp.Diagnostics)
+
+	var resumed CollectionSourceResourceModel
+	require.False(t, readResp.State.Get(ctx, &resumed).HasError())
+	assert.False(t, resumed.Suspended.ValueBool())
+	assert.Equal(t, "30m", resumed.ResumeAfter.ValueString())
+	assert.True(t, resumed.ResumeAt.IsNull())
+
+	// while Rockset resuming it at resume_at isn't
+	planned = resumed
+	planned.Suspended = types.BoolValue(true)
+	planned.ResumeAfter = types.StringValue("1s")
+	planned.State = types.StringUnknown()
+	planned.ResumeAt = types.StringUnknown()
+	plan = tfsdk.Plan{Schema: s, Raw: null}
+	require.False(t, plan.Set(ctx, &planned).HasError())
+
+	updateResp = resource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: plan, State: readResp.State}, &updateResp)
+	require.False(t, updateResp.Diagnostics.HasError(), updateResp.Diagnostics)
+	require.False(t, updateResp.State.Get(ctx, &updated).HasError())
+
+	time.Sleep(1100 * time.Millisecond)
+	readResp = resource.ReadResponse{State: updateResp.State}
+	r.Read(ctx, resource.ReadRequest{State: updateResp.State}, &readResp)
+	require.False(t, readResp.Diagnostics.HasError(), readResp.Diagnostics)
+
+	var autoResumed CollectionSourceResourceModel
+	require.False(t, readResp.State.Get(ctx, &autoResumed).HasError())
+	assert.True(t, autoResumed.Suspended.ValueBool())
+	assert.Equal(t, updated.ResumeAt, autoResumed.ResumeAt)
+	assert.NotEqual(t, client.SourceSuspendedState, autoResumed.State.ValueString())
+
+	// so it only is resumed in the state once it should stay resumed
+	planned = autoResumed
+	planned.Suspended = types.BoolValue(false)
+	planned.State = types.StringUnknown()
+	planned.ResumeAt = types.StringUnknown()
+	plan = tfsdk.Plan{Schema: s, Raw: null}
+	require.False(t, plan.Set(ctx, &planned).HasError())
+
+	updateResp = resource.UpdateResponse{State: readResp.State}
+	r.Update(ctx, resource.UpdateRequest{Plan: plan, State: readResp.State}, &updateResp)
+	require.False(t, updateResp.Diagnostics.HasError(), updateResp.Diagnostics)
+	require.False(t, updateResp.State.Get(ctx, &updated).HasError())
+	assert.False(t, updated.Suspended.ValueBool())
+	assert.True(t, updated.ResumeAt.IsNull())
+
+	deleteResp := resource.DeleteResponse{State: createResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: createResp.State}, &deleteResp)
+	require.False(t, deleteResp.Diagnostics.HasError(), deleteResp.Diagnostics)
+
+	// a source which has been deleted outside of Terraform is removed from the state
+	readResp = resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	require.False(t, readResp.Diagnostics.HasError(), readResp.Diagnostics)
+	assert.True(t, readResp.State.Raw.IsNull())
+
+	importResp = resource.ImportStateResponse{State: tfsdk.State{Schema: s, Raw: null}}
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "commons.orders"}, &importResp)
+	assert.True(t, importResp.Diagnostics.HasError())
+}
+
+func TestCollectionSourceResource_ValidateConfig(t *testing.T) {
+	ctx := context.TODO()
+	r := &CollectionSourceResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	tests := []struct {
+		name  string
+		model CollectionSourceResourceModel
+		valid bool
+	}{
+		{
+			name:  "none",
+			model: CollectionSourceResourceModel{},
+		},
+		{
+			name: "one",
+			model: CollectionSourceResourceModel{
+				S3: &S3SourceModel{Bucket: types.StringValue("bucket")},
+			},
+			valid: true,
+		},
+		{
+			name: "resume after",
+			model: CollectionSourceResourceModel{
+				ResumeAfter: types.StringValue("12h"),
+				S3:          &S3SourceModel{Bucket: types.StringValue("bucket")},
+			},
+			valid: true,
+		},
+		{
+			name: "invalid resume after",
+			model: CollectionSourceResourceModel{
+				ResumeAfter: types.StringValue("1d"),
+				S3:          &S3SourceModel{Bucket: types.StringValue("bucket")},
+			},
+		},
+		{
+			name: "two",
+			model: CollectionSourceResourceModel{
+				S3:  &S3SourceModel{Bucket: types.StringValue("bucket")},
+				GCS: &GCSSourceModel{Bucket: types.StringValue("bucket")},
+			},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			// the config is built as a state, as tfsdk.Config can't be set from a model
+			state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+			require.False(t, state.Set(ctx, &tst.model).HasError())
+
+			var resp resource.ValidateConfigResponse
+			r.ValidateConfig(ctx, resource.ValidateConfigRequest{
+				Config: tfsdk.Config{Schema: s, Raw: state.Raw},
+			}, &resp)
+			assert.Equal(t, !tst.valid, resp.Diagnostics.HasError())
+		})
+	}
+}
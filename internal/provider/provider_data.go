@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/rockset/rockset-go-client"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// ProviderData is passed from the provider to the resources and data sources when they are configured.
+type ProviderData struct {
+	lazy *client.Lazy
+	// ReadOnly prevents resources from being created, updated or deleted.
+	ReadOnly bool
+}
+
+// Client returns the Rockset client, which is created the first time it is needed. If it can't be created,
+// an error is added to diags and nil is returned.
+func (p ProviderData) Client(ctx context.Context, diags *diag.Diagnostics) *rockset.RockClient {
+	rc, err := p.lazy.Client(ctx)
+	if err != nil {
+		diags.AddError("Failed to create Rockset client", err.Error())
+		return nil
+	}
+
+	return rc
+}
+
+// CheckReadOnly adds an error to diags if the provider is read-only, and must be called by resources
+// before they make any API call to create, update or delete.
+func (p ProviderData) CheckReadOnly(diags *diag.Diagnostics, operation, typeName string) bool {
+	if !p.ReadOnly {
+		return true
+	}
+
+	diags.AddError(fmt.Sprintf("can't %s %s: %s", operation, typeName, client.ErrReadOnly),
+		"Set read_only = false in the provider configuration to allow changes.")
+
+	return false
+}
@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderData_CheckReadOnly(t *testing.T) {
+	var diags diag.Diagnostics
+
+	assert.True(t, ProviderData{}.CheckReadOnly(&diags, "create", "rockset_test"))
+	assert.False(t, diags.HasError())
+
+	assert.False(t, ProviderData{ReadOnly: true}.CheckReadOnly(&diags, "delete", "rockset_test"))
+	assert.True(t, diags.HasError())
+	assert.Equal(t, "can't delete rockset_test: the provider is configured with read_only = true", diags[0].Summary())
+}
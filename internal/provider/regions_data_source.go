@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RegionsDataSource{}
+
+func NewRegionsDataSource() datasource.DataSource {
+	return &RegionsDataSource{}
+}
+
+// RegionsDataSource defines the data source implementation.
+type RegionsDataSource struct{}
+
+// RegionsDataSourceModel describes the data source data model.
+type RegionsDataSourceModel struct {
+	Regions    []RegionModel     `tfsdk:"regions"`
+	APIServers map[string]string `tfsdk:"api_servers"`
+}
+
+type RegionModel struct {
+	ID          types.String `tfsdk:"id"`
+	APIServer   types.String `tfsdk:"api_server"`
+	CloudRegion types.String `tfsdk:"cloud_region"`
+	Description types.String `tfsdk:"description"`
+}
+
+func (d *RegionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_regions"
+}
+
+func (d *RegionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Rockset regions and their API servers.",
+		Attributes: map[string]schema.Attribute{
+			"regions": schema.ListNestedAttribute{
+				MarkdownDescription: "The Rockset regions, sorted by ID.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Region ID, e.g. `usw2a1`",
+							Computed:            true,
+						},
+						"api_server": schema.StringAttribute{
+							MarkdownDescription: "API server of the region",
+							Computed:            true,
+						},
+						"cloud_region": schema.StringAttribute{
+							MarkdownDescription: "Cloud provider region which hosts the Rockset region, e.g. `us-west-2`",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Name of the region",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"api_servers": schema.MapAttribute{
+				MarkdownDescription: "Map of region ID to API server.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *RegionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	data := RegionsDataSourceModel{
+		APIServers: make(map[string]string),
+	}
+
+	for _, id := range client.RegionIDs() {
+		r := client.Regions[id]
+		data.Regions = append(data.Regions, RegionModel{
+			ID:          types.StringValue(r.ID),
+			APIServer:   types.StringValue(r.APIServer),
+			CloudRegion: types.StringValue(r.CloudRegion),
+			Description: types.StringValue(r.Description),
+		})
+		data.APIServers[r.ID] = r.APIServer
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccRegionsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRegionsDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.rockset_regions.all", "regions.#", "4"),
+					resource.TestCheckResourceAttr("data.rockset_regions.all", "regions.0.id", "aps1a1"),
+					resource.TestCheckResourceAttr("data.rockset_regions.all", "api_servers.usw2a1",
+						"api.usw2a1.rockset.com"),
+				),
+			},
+		},
+	})
+}
+
+const testAccRegionsDataSourceConfig = `
+data "rockset_regions" "all" {}
+`
@@ -2,15 +2,19 @@ package provider
 
 import (
 	"context"
-	"fmt"
-	"os"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/rockset/rockset-go-client"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
 )
 
 var (
@@ -29,60 +33,105 @@ func New(version string) func() provider.Provider {
 	}
 }
 
+// RocksetProviderModel describes the provider configuration. The values can be unknown until apply,
+// e.g. when the API key is created by another provider, so the client is created when it first is needed.
 type RocksetProviderModel struct {
-	APIKey    *string `tfsdk:"api_key"`
-	APIServer *string `tfsdk:"api_server"`
-	OrgID     *string `tfsdk:"organization_id"`
+	APIKey                types.String  `tfsdk:"api_key"`
+	APIServer             types.String  `tfsdk:"api_server"`
+	Region                types.String  `tfsdk:"region"`
+	OrgID                 types.String  `tfsdk:"organization_id"`
+	APIKeyCommand         types.String  `tfsdk:"api_key_command"`
+	Profile               types.String  `tfsdk:"profile"`
+	CredentialsFile       types.String  `tfsdk:"credentials_file"`
+	ReadOnly              types.Bool    `tfsdk:"read_only"`
+	SkipSQLValidation     types.Bool    `tfsdk:"skip_sql_validation"`
+	MaxRetries            types.Int64   `tfsdk:"max_retries"`
+	MinBackoff            types.String  `tfsdk:"min_backoff"`
+	MaxBackoff            types.String  `tfsdk:"max_backoff"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
 }
 
-const providerUserAgent = "terraform-provider-rockset"
+// configured checks if the value is set, and known.
+func configured(v attr.Value) bool {
+	return !v.IsNull() && !v.IsUnknown()
+}
 
-func (p *rocksetProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	var data RocksetProviderModel
+// retryConfig reads the retry settings from the provider configuration, and uses the default for those not set.
+func (m RocksetProviderModel) retryConfig() (client.RetryConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	cfg := client.DefaultRetryConfig()
 
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
-	if resp.Diagnostics.HasError() {
-		return
+	if configured(m.MaxRetries) {
+		if m.MaxRetries.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("max_retries"), "Invalid max_retries", "max_retries must be at least 0")
+		}
+		cfg.MaxRetries = int(m.MaxRetries.ValueInt64())
 	}
-
-	var opts = []rockset.RockOption{
-		rockset.WithUserAgent(fmt.Sprintf("%s/%s", providerUserAgent, p.version)),
+	if configured(m.MinBackoff) {
+		d, err := time.ParseDuration(m.MinBackoff.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("min_backoff"), "Invalid min_backoff", err.Error())
+		}
+		cfg.MinBackoff = d
 	}
-
-	if data.APIKey != nil {
-		opts = append(opts, rockset.WithAPIKey(*data.APIKey))
+	if configured(m.MaxBackoff) {
+		d, err := time.ParseDuration(m.MaxBackoff.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("max_backoff"), "Invalid max_backoff", err.Error())
+		}
+		cfg.MaxBackoff = d
 	}
-	if data.APIServer != nil {
-		opts = append(opts, rockset.WithAPIServer(*data.APIServer))
+	if configured(m.MaxConcurrentRequests) {
+		if m.MaxConcurrentRequests.ValueInt64() < 0 {
+			diags.AddAttributeError(path.Root("max_concurrent_requests"), "Invalid max_concurrent_requests",
+				"max_concurrent_requests must be at least 0")
+		}
+		cfg.MaxConcurrentRequests = int(m.MaxConcurrentRequests.ValueInt64())
 	}
-	if os.Getenv("ROCKSET_DEBUG") != "" {
-		opts = append(opts, rockset.WithHTTPDebug())
+	if configured(m.RequestsPerSecond) {
+		if m.RequestsPerSecond.ValueFloat64() < 0 {
+			diags.AddAttributeError(path.Root("requests_per_second"), "Invalid requests_per_second",
+				"requests_per_second must be at least 0")
+		}
+		cfg.RequestsPerSecond = m.RequestsPerSecond.ValueFloat64()
 	}
 
-	rc, err := rockset.NewClient(opts...)
-	if err != nil {
-		// TODO create a helper function that turns a Rockset error into a Diagnostic
-		resp.Diagnostics.AddError("Failed to create Rockset client", err.Error())
+	return cfg, diags
+}
+
+func (p *rocksetProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data RocksetProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	org, err := rc.GetOrganization(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to get organization", err.Error())
+	retry, diags := data.retryConfig()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	// validate that the expected org id matches the org id of the api key
-	if data.OrgID != nil {
-		if org.GetId() != *data.OrgID {
-			resp.Diagnostics.AddError("Organization ID does not match",
-				"The organization configured in the provider does not match the organization of the API key")
-			return
-		}
-	}
 
-	tflog.Info(ctx, "connected to Rockset", map[string]interface{}{"org_id": org.GetId()})
+	cfg := client.Config{
+		APIKey:          data.APIKey.ValueString(),
+		APIServer:       data.APIServer.ValueString(),
+		Region:          data.Region.ValueString(),
+		OrgID:           data.OrgID.ValueString(),
+		APIKeyCommand:   data.APIKeyCommand.ValueString(),
+		Profile:         data.Profile.ValueString(),
+		CredentialsFile: data.CredentialsFile.ValueString(),
+		Version:         p.version,
+		Retry:           retry,
+	}
 
-	resp.DataSourceData = rc
+	pd := ProviderData{
+		lazy:     client.Shared(cfg),
+		ReadOnly: data.ReadOnly.ValueBool(),
+	}
+	resp.DataSourceData = pd
+	resp.ResourceData = pd
 }
 
 func (p *rocksetProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -96,11 +145,14 @@ func (p *rocksetProvider) DataSources(ctx context.Context) []func() datasource.D
 		func() datasource.DataSource {
 			return &CollectionSourceDataSource{}
 		},
+		NewRegionsDataSource,
 	}
 }
 
 func (p *rocksetProvider) Resources(ctx context.Context) []func() resource.Resource {
-	return []func() resource.Resource{}
+	return []func() resource.Resource{
+		NewCollectionSourceResource,
+	}
 }
 
 func (p *rocksetProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
@@ -115,12 +167,72 @@ func (p *rocksetProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Optional:            true,
 				MarkdownDescription: "The API server for accessing Rockset",
 			},
+			"region": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The Rockset region to connect to, which is used to look up the API server. " +
+					"Can't be used together with `api_server`. If neither is set, the API server is discovered from " +
+					"the clusters of the organization. Valid regions are: " + strings.Join(client.RegionIDs(), ", "),
+			},
 			"organization_id": schema.StringAttribute{
 				Optional: true,
 				MarkdownDescription: "The ID of the organization to connect to. " +
 					"If this is set, the provider will validate that the organization_id matches the organization_id " +
 					"of the api key. If it does not match, the provider will return an error.\n",
 			},
+			"api_key_command": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "A command which is run using the shell to fetch the API key, " +
+					"e.g. from a password manager. The output of the command is used as the API key. " +
+					"Can't be used together with `api_key`.",
+			},
+			"profile": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The name of the context in the credentials file to read the API key, API server and " +
+					"organization ID from. Arguments set in the provider block take precedence over the profile. " +
+					"If not present it will be sourced from the `ROCKSET_PROFILE` environment variable.",
+			},
+			"credentials_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The credentials file to read the `profile` from. " +
+					"Defaults to `~/.config/rockset/credentials.yaml`, " +
+					"or the `ROCKSET_CREDENTIALS_FILE` environment variable if it is set.",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Prevents the provider from creating, updating or deleting any resources, " +
+					"which fail before any API call is made. Reads and data sources still work, " +
+					"so it can be used to run `terraform plan` with an API key which never should change anything.",
+			},
+			"skip_sql_validation": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Don't send the SQL of views and query lambdas to Rockset for " +
+					"validation when planning, e.g. for plans which can't reach the API server. The SQL is still " +
+					"checked offline, e.g. for query lambda parameters without a default_parameter, and the collections, " +
+					"aliases and views it references are still looked up, to warn about those which don't exist.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Maximum number of times an API call which failed with a retryable error, " +
+					"e.g. HTTP 429 or 503, is retried. Defaults to 5.",
+			},
+			"min_backoff": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "How long to wait before the first retry of an API call, " +
+					"which is doubled for every subsequent retry. Defaults to `1s`.",
+			},
+			"max_backoff": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "The longest time to wait between two retries of an API call, " +
+					"unless the API asks for a longer wait using the `Retry-After` header. Defaults to `30s`.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum number of concurrent API calls. Defaults to no limit.",
+			},
+			"requests_per_second": schema.Float64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum number of API calls per second. Defaults to no limit.",
+			},
 		},
 	}
 }
@@ -1,11 +1,19 @@
 package provider
 
 import (
+	"context"
 	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/rockset"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -24,3 +32,62 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("ROCKSET_APISERVER must be set for acceptance tests")
 	}
 }
+
+// TestProviderSchemasMatch verifies that the plugin framework and the SDKv2 provider have the same provider schema,
+// as the mux server rejects providers with different schemas.
+func TestProviderSchemasMatch(t *testing.T) {
+	ctx := context.TODO()
+
+	sdkProvider, err := tf5to6server.UpgradeServer(ctx, rockset.ProviderServer)
+	require.NoError(t, err)
+
+	mux, err := tf6muxserver.NewMuxServer(ctx,
+		providerserver.NewProtocol6(New("test")()),
+		func() tfprotov6.ProviderServer { return sdkProvider },
+	)
+	require.NoError(t, err)
+
+	resp, err := mux.ProviderServer().GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+
+	for _, d := range resp.Diagnostics {
+		assert.NotEqual(t, tfprotov6.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+	}
+}
+
+// TestConfigureProviderUnknownCredentials verifies that the provider can be configured with credentials which are
+// unknown until apply, and without making any API call.
+func TestConfigureProviderUnknownCredentials(t *testing.T) {
+	ctx := context.TODO()
+
+	sdkProvider, err := tf5to6server.UpgradeServer(ctx, rockset.ProviderServer)
+	require.NoError(t, err)
+
+	mux, err := tf6muxserver.NewMuxServer(ctx,
+		providerserver.NewProtocol6(New("test")()),
+		func() tfprotov6.ProviderServer { return sdkProvider },
+	)
+	require.NoError(t, err)
+	server := mux.ProviderServer()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+
+	typ := schemaResp.Provider.ValueType().(tftypes.Object)
+	attrs := make(map[string]tftypes.Value)
+	for name, attrType := range typ.AttributeTypes {
+		attrs[name] = tftypes.NewValue(attrType, nil)
+	}
+	attrs["api_key"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	attrs["api_server"] = tftypes.NewValue(tftypes.String, "localhost:1")
+
+	config, err := tfprotov6.NewDynamicValue(typ, tftypes.NewValue(typ, attrs))
+	require.NoError(t, err)
+
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{Config: &config})
+	require.NoError(t, err)
+
+	for _, d := range resp.Diagnostics {
+		assert.NotEqual(t, tfprotov6.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+	}
+}
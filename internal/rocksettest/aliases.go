@@ -0,0 +1,143 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type alias struct {
+	openapi.Alias
+	deleted bool
+}
+
+func (s *Server) createAlias(w http.ResponseWriter, r *request) {
+	ws := r.params["workspace"]
+	var req openapi.CreateAliasRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid alias name %s", req.Name)
+		return
+	}
+	if !s.activeWorkspace(w, ws) {
+		return
+	}
+	if _, found := s.aliases[path(ws, req.Name)]; found {
+		alreadyExists(w, "Alias", path(ws, req.Name))
+		return
+	}
+	if !s.collectionsExist(w, req.Collections) {
+		return
+	}
+
+	now := timestamp()
+	a := &alias{Alias: openapi.Alias{
+		Name:         openapi.PtrString(req.Name),
+		Workspace:    openapi.PtrString(ws),
+		Description:  req.Description,
+		Collections:  req.Collections,
+		CreatorEmail: openapi.PtrString(r.user),
+		CreatedAt:    openapi.PtrString(now),
+		ModifiedAt:   openapi.PtrString(now),
+		State:        openapi.PtrString("CREATED"),
+	}}
+	s.aliases[path(ws, req.Name)] = a
+
+	writeData(w, http.StatusOK, a.Alias)
+}
+
+func (s *Server) getAlias(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["alias"]
+	a, found := s.aliases[path(ws, name)]
+	if !found {
+		notFound(w, "Alias", path(ws, name))
+		return
+	}
+
+	if a.deleted {
+		delete(s.aliases, path(ws, name))
+	}
+
+	writeData(w, http.StatusOK, a.Alias)
+}
+
+func (s *Server) listAliases(w http.ResponseWriter, r *request) {
+	ws, inWorkspace := r.params["workspace"]
+	if inWorkspace && !s.activeWorkspace(w, ws) {
+		return
+	}
+
+	paths := make([]string, 0, len(s.aliases))
+	for p, a := range s.aliases {
+		if !inWorkspace || a.GetWorkspace() == ws {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	list := make([]openapi.Alias, 0, len(paths))
+	for _, p := range paths {
+		list = append(list, s.aliases[p].Alias)
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateAlias(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["alias"]
+	a, found := s.aliases[path(ws, name)]
+	if !found || a.deleted {
+		notFound(w, "Alias", path(ws, name))
+		return
+	}
+
+	var req openapi.UpdateAliasRequest
+	if !r.decode(w, &req) {
+		return
+	}
+	if !s.collectionsExist(w, req.Collections) {
+		return
+	}
+
+	a.Collections = req.Collections
+	a.Description = req.Description
+	a.ModifiedAt = openapi.PtrString(timestamp())
+
+	writeData(w, http.StatusOK, a.Alias)
+}
+
+func (s *Server) deleteAlias(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["alias"]
+	a, found := s.aliases[path(ws, name)]
+	if !found || a.deleted {
+		notFound(w, "Alias", path(ws, name))
+		return
+	}
+
+	a.deleted = true
+	a.State = openapi.PtrString("DELETED")
+
+	writeData(w, http.StatusOK, a.Alias)
+}
+
+// collectionsExist checks that all collections, given as workspace.collection, exist, and writes an error response
+// if they don't.
+func (s *Server) collectionsExist(w http.ResponseWriter, paths []string) bool {
+	if len(paths) == 0 {
+		badRequest(w, "at least one collection is required")
+		return false
+	}
+
+	for _, p := range paths {
+		if c, found := s.collections[p]; !found || c.deleted {
+			notFound(w, "Collection", p)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,155 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type apiKey struct {
+	// user is the email of the user who owns the key
+	user string
+	key  openapi.ApiKey
+}
+
+func apiKeyID(user, name string) string {
+	return user + "/" + name
+}
+
+// createAPIKey creates an API key for the current user. Only the response contains the full key, which can be
+// used to authenticate with the server.
+func (s *Server) createAPIKey(w http.ResponseWriter, r *request) {
+	var req openapi.CreateApiKeyRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid API key name %s", req.Name)
+		return
+	}
+	if _, found := s.apiKeys[apiKeyID(r.user, req.Name)]; found {
+		alreadyExists(w, "API key", req.Name)
+		return
+	}
+	if req.Role != nil && !s.rolesExist(w, []string{req.GetRole()}) {
+		return
+	}
+
+	k := &apiKey{
+		user: r.user,
+		key: openapi.ApiKey{
+			Name:       req.Name,
+			Key:        strings.ReplaceAll(newID()+newID(), "-", ""),
+			Role:       req.Role,
+			ExpiryTime: req.ExpiryTime,
+			CreatedBy:  openapi.PtrString(r.user),
+			CreatedAt:  openapi.PtrString(timestamp()),
+			State:      openapi.PtrString("ACTIVE"),
+		},
+	}
+	s.apiKeys[apiKeyID(r.user, req.Name)] = k
+
+	writeData(w, http.StatusOK, k.key)
+}
+
+func (s *Server) getAPIKey(w http.ResponseWriter, r *request) {
+	k, ok := s.apiKey(w, r)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, k.obfuscated())
+}
+
+func (s *Server) listAPIKeys(w http.ResponseWriter, r *request) {
+	user := s.apiKeyUser(r)
+	if _, found := s.users[user]; !found {
+		notFound(w, "User", user)
+		return
+	}
+
+	ids := make([]string, 0, len(s.apiKeys))
+	for id, k := range s.apiKeys {
+		if k.user == user {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	list := make([]openapi.ApiKey, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, s.apiKeys[id].obfuscated())
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateAPIKey(w http.ResponseWriter, r *request) {
+	k, ok := s.apiKey(w, r)
+	if !ok {
+		return
+	}
+
+	var req openapi.UpdateApiKeyRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.State != nil {
+		if state := req.GetState(); state != "ACTIVE" && state != "SUSPENDED" {
+			badRequest(w, "invalid API key state %s", state)
+			return
+		}
+		k.key.State = req.State
+	}
+	if req.ExpiryTime != nil {
+		k.key.ExpiryTime = req.ExpiryTime
+	}
+	if req.GetClearExpiryTime() {
+		k.key.ExpiryTime = nil
+	}
+
+	writeData(w, http.StatusOK, k.obfuscated())
+}
+
+func (s *Server) deleteAPIKey(w http.ResponseWriter, r *request) {
+	k, ok := s.apiKey(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.apiKeys, apiKeyID(k.user, k.key.Name))
+	writeData(w, http.StatusOK, k.obfuscated())
+}
+
+// apiKey returns the API key of the request, and writes an error response if it doesn't exist.
+func (s *Server) apiKey(w http.ResponseWriter, r *request) (*apiKey, bool) {
+	user, name := s.apiKeyUser(r), r.params["name"]
+	k, found := s.apiKeys[apiKeyID(user, name)]
+	if !found {
+		notFound(w, "API key", name)
+		return nil, false
+	}
+
+	return k, true
+}
+
+// apiKeyUser returns the user of the API key request, where self is the current user.
+func (s *Server) apiKeyUser(r *request) string {
+	if user := r.params["user"]; user != "self" {
+		return user
+	}
+
+	return r.user
+}
+
+// obfuscated returns the key like the API does when it isn't created: only the last characters are visible.
+func (k *apiKey) obfuscated() openapi.ApiKey {
+	data := k.key
+	data.Key = "****" + data.Key[len(data.Key)-4:]
+
+	return data
+}
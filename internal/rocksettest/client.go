@@ -0,0 +1,29 @@
+package rocksettest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/retry"
+)
+
+// NewClient returns a client of the server, which authenticates with the APIKey and retries without waiting long.
+// The options are applied after those, so they can e.g. use another API key.
+func NewClient(t testing.TB, srv *Server, options ...rockset.RockOption) *rockset.RockClient {
+	t.Helper()
+
+	options = append([]rockset.RockOption{
+		rockset.WithAPIServer(srv.URL),
+		rockset.WithAPIKey(APIKey),
+		rockset.WithHTTPClient(srv.Client()),
+		rockset.WithRetry(retry.Exponential{WaitInterval: time.Millisecond}),
+	}, options...)
+
+	rc, err := rockset.NewClient(options...)
+	if err != nil {
+		t.Fatalf("failed to create client of the fake Rockset API: %v", err)
+	}
+
+	return rc
+}
@@ -0,0 +1,283 @@
+package rocksettest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+// documentsPerRead is the number of documents each source of a ready collection ingests between two reads.
+const documentsPerRead = 100
+
+// documentSize is the number of bytes each document adds to the size of a collection.
+const documentSize = 1024
+
+type collection struct {
+	openapi.Collection
+	status  lifecycle
+	deleted bool
+	offset  int64
+}
+
+func (s *Server) createCollection(w http.ResponseWriter, r *request) {
+	ws := r.params["workspace"]
+	var req openapi.CreateCollectionRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	name := req.GetName()
+	if !nameRe.MatchString(name) {
+		badRequest(w, "invalid collection name %s", name)
+		return
+	}
+	if !s.activeWorkspace(w, ws) {
+		return
+	}
+	if _, found := s.collections[path(ws, name)]; found {
+		alreadyExists(w, "Collection", path(ws, name))
+		return
+	}
+
+	sources := make([]openapi.Source, 0, len(req.Sources))
+	for _, src := range req.Sources {
+		if !s.newSource(w, &src) {
+			return
+		}
+		sources = append(sources, src)
+	}
+
+	c := &collection{
+		Collection: openapi.Collection{
+			Name:                   openapi.PtrString(name),
+			Workspace:              openapi.PtrString(ws),
+			Description:            req.Description,
+			Rrn:                    openapi.PtrString(rrn("collection", newID())),
+			CreatedAt:              openapi.PtrString(timestamp()),
+			CreatedBy:              openapi.PtrString(r.user),
+			RetentionSecs:          req.RetentionSecs,
+			FieldMappingQuery:      req.FieldMappingQuery,
+			ClusteringKey:          req.ClusteringKey,
+			StorageCompressionType: req.StorageCompressionType,
+			Sources:                sources,
+			InsertOnly:             openapi.PtrBool(false),
+			ReadOnly:               openapi.PtrBool(false),
+			Stats:                  &openapi.CollectionStats{DocCount: openapi.PtrInt64(0)},
+		},
+		status: newLifecycle("CREATED", "READY"),
+	}
+	if c.StorageCompressionType == nil {
+		c.StorageCompressionType = openapi.PtrString("LZ4")
+	}
+	s.collections[path(ws, name)] = c
+
+	writeData(w, http.StatusOK, c.data())
+}
+
+func (s *Server) getCollection(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	c, found := s.collections[path(ws, name)]
+	if !found {
+		notFound(w, "Collection", path(ws, name))
+		return
+	}
+
+	if c.deleted {
+		delete(s.collections, path(ws, name))
+		writeData(w, http.StatusOK, c.data())
+		return
+	}
+
+	c.resumeDueSources()
+	c.status.next()
+	if c.status.done() {
+		c.ingest()
+	}
+
+	writeData(w, http.StatusOK, c.data())
+}
+
+// ingest simulates that the sources of a ready collection ingest documents, unless they are suspended.
+func (c *collection) ingest() {
+	for i := range c.Sources {
+		src := &c.Sources[i]
+		if src.Status.GetState() == sourceSuspended {
+			continue
+		}
+		src.Status.State = openapi.PtrString("WATCHING")
+		src.Status.TotalProcessedItems = openapi.PtrInt64(src.Status.GetTotalProcessedItems() + documentsPerRead)
+		if src.Kafka != nil {
+			src.Kafka.Status.State = openapi.PtrString("ACTIVE")
+			src.Kafka.Status.NumDocumentsProcessed = src.Status.TotalProcessedItems
+		}
+		c.Stats.DocCount = openapi.PtrInt64(c.Stats.GetDocCount() + documentsPerRead)
+	}
+}
+
+func (s *Server) listCollections(w http.ResponseWriter, r *request) {
+	ws, inWorkspace := r.params["workspace"]
+	if inWorkspace && !s.activeWorkspace(w, ws) {
+		return
+	}
+
+	paths := make([]string, 0, len(s.collections))
+	for p, c := range s.collections {
+		if !inWorkspace || c.GetWorkspace() == ws {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	list := make([]openapi.Collection, 0, len(paths))
+	for _, p := range paths {
+		list = append(list, s.collections[p].data())
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateCollection(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	c, found := s.collections[path(ws, name)]
+	if !found || c.deleted {
+		notFound(w, "Collection", path(ws, name))
+		return
+	}
+
+	var req openapi.UpdateCollectionRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.Description != nil {
+		c.Description = req.Description
+	}
+	if req.FieldMappingQuery != nil {
+		c.FieldMappingQuery = req.FieldMappingQuery
+	}
+
+	writeData(w, http.StatusOK, c.data())
+}
+
+func (s *Server) deleteCollection(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	c, found := s.collections[path(ws, name)]
+	if !found || c.deleted {
+		notFound(w, "Collection", path(ws, name))
+		return
+	}
+
+	for _, a := range s.aliases {
+		for _, p := range a.Collections {
+			if p == path(ws, name) {
+				badRequest(w, "Collection %s is referenced by alias %s", p, path(a.GetWorkspace(), a.GetName()))
+				return
+			}
+		}
+	}
+
+	c.deleted = true
+	c.status = newLifecycle("DELETED")
+	for id, m := range s.mounts {
+		if m.GetCollectionPath() == path(ws, name) {
+			delete(s.mounts, id)
+		}
+	}
+
+	writeData(w, http.StatusOK, c.data())
+}
+
+func (s *Server) addDocuments(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	c, found := s.collections[path(ws, name)]
+	if !found || c.deleted {
+		notFound(w, "Collection", path(ws, name))
+		return
+	}
+
+	var req openapi.AddDocumentsRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	statuses := make([]openapi.DocumentStatus, 0, len(req.Data))
+	for _, doc := range req.Data {
+		id, ok := doc["_id"].(string)
+		if !ok {
+			id = newID()
+		}
+		statuses = append(statuses, openapi.DocumentStatus{
+			Collection: openapi.PtrString(name),
+			Id:         openapi.PtrString(id),
+			Status:     openapi.PtrString("ADDED"),
+		})
+	}
+	c.Stats.DocCount = openapi.PtrInt64(c.Stats.GetDocCount() + int64(len(req.Data)))
+	c.offset++
+
+	writeJSON(w, http.StatusOK, openapi.AddDocumentsResponse{
+		Data:       statuses,
+		LastOffset: openapi.PtrString(fmt.Sprintf("f1:0:%d:0:0", c.offset)),
+	})
+}
+
+func (s *Server) deleteDocuments(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	c, found := s.collections[path(ws, name)]
+	if !found || c.deleted {
+		notFound(w, "Collection", path(ws, name))
+		return
+	}
+
+	var req openapi.DeleteDocumentsRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	statuses := make([]openapi.DocumentStatus, 0, len(req.Data))
+	for _, doc := range req.Data {
+		statuses = append(statuses, openapi.DocumentStatus{
+			Collection: openapi.PtrString(name),
+			Id:         openapi.PtrString(doc.Id),
+			Status:     openapi.PtrString("DELETED"),
+		})
+	}
+	count := c.Stats.GetDocCount() - int64(len(req.Data))
+	if count < 0 {
+		count = 0
+	}
+	c.Stats.DocCount = &count
+	c.offset++
+
+	writeJSON(w, http.StatusOK, openapi.DeleteDocumentsResponse{
+		Data:       statuses,
+		LastOffset: openapi.PtrString(fmt.Sprintf("f1:0:%d:0:0", c.offset)),
+	})
+}
+
+// commitOffsets reports that all offsets have been committed, as documents are queryable as soon as they are added.
+func (s *Server) commitOffsets(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	if c, found := s.collections[path(ws, name)]; !found || c.deleted {
+		notFound(w, "Collection", path(ws, name))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, openapi.GetCollectionCommit{
+		Data: &openapi.GetCollectionCommitData{Passed: openapi.PtrBool(true)},
+	})
+}
+
+func (c *collection) data() openapi.Collection {
+	data := c.Collection
+	data.Status = openapi.PtrString(c.status.current())
+	if c.Stats != nil {
+		stats := *c.Stats
+		stats.TotalSize = openapi.PtrInt64(stats.GetDocCount() * documentSize)
+		data.Stats = &stats
+	}
+
+	return data
+}
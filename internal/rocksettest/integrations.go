@@ -0,0 +1,203 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type integration struct {
+	openapi.Integration
+	// topics is the state of the topics of a Kafka integration which doesn't use v3
+	topics lifecycle
+}
+
+func (s *Server) createIntegration(w http.ResponseWriter, r *request) {
+	var req openapi.CreateIntegrationRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid integration name %s", req.Name)
+		return
+	}
+	if _, found := s.integrations[req.Name]; found {
+		alreadyExists(w, "Integration", req.Name)
+		return
+	}
+
+	i := &integration{
+		Integration: openapi.Integration{
+			Name:             req.Name,
+			Description:      req.Description,
+			CreatedAt:        openapi.PtrString(timestamp()),
+			CreatedBy:        r.user,
+			OwnerEmail:       openapi.PtrString(r.user),
+			IsWriteEnabled:   req.IsWriteEnabled,
+			AzureBlobStorage: req.AzureBlobStorage,
+			AzureEventHubs:   req.AzureEventHubs,
+			AzureServiceBus:  req.AzureServiceBus,
+			Dynamodb:         req.Dynamodb,
+			Gcs:              req.Gcs,
+			Kafka:            req.Kafka,
+			Kinesis:          req.Kinesis,
+			Mongodb:          req.Mongodb,
+			S3:               req.S3,
+			Snowflake:        req.Snowflake,
+		},
+		topics: newLifecycle("NO_DOCS_YET", "ACTIVE"),
+	}
+	s.integrations[req.Name] = i
+
+	writeData(w, http.StatusOK, s.integrationData(i))
+}
+
+func (s *Server) getIntegration(w http.ResponseWriter, r *request) {
+	name := r.params["integration"]
+	i, found := s.integrations[name]
+	if !found {
+		notFound(w, "Integration", name)
+		return
+	}
+
+	i.topics.next()
+	writeData(w, http.StatusOK, s.integrationData(i))
+}
+
+func (s *Server) listIntegrations(w http.ResponseWriter, _ *request) {
+	names := make([]string, 0, len(s.integrations))
+	for name := range s.integrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]openapi.Integration, 0, len(names))
+	for _, name := range names {
+		list = append(list, s.integrationData(s.integrations[name]))
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateIntegration(w http.ResponseWriter, r *request) {
+	name := r.params["integration"]
+	i, found := s.integrations[name]
+	if !found {
+		notFound(w, "Integration", name)
+		return
+	}
+
+	var req openapi.UpdateIntegrationRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.Description != nil {
+		i.Description = req.Description
+	}
+	if req.IsWriteEnabled != nil {
+		i.IsWriteEnabled = req.IsWriteEnabled
+	}
+	if req.Kafka != nil {
+		i.Kafka = req.Kafka
+		i.topics = newLifecycle("NO_DOCS_YET", "ACTIVE")
+	}
+	if req.Mongodb != nil {
+		i.Mongodb = req.Mongodb
+	}
+	if req.Gcs != nil {
+		i.Gcs = req.Gcs
+	}
+	if req.S3 != nil {
+		i.S3 = req.S3
+	}
+	if req.Kinesis != nil {
+		i.Kinesis = req.Kinesis
+	}
+	if req.Dynamodb != nil {
+		i.Dynamodb = req.Dynamodb
+	}
+	if req.AzureBlobStorage != nil {
+		i.AzureBlobStorage = req.AzureBlobStorage
+	}
+	if req.AzureEventHubs != nil {
+		i.AzureEventHubs = req.AzureEventHubs
+	}
+	if req.AzureServiceBus != nil {
+		i.AzureServiceBus = req.AzureServiceBus
+	}
+	if req.Snowflake != nil {
+		i.Snowflake = req.Snowflake
+	}
+
+	writeData(w, http.StatusOK, s.integrationData(i))
+}
+
+func (s *Server) deleteIntegration(w http.ResponseWriter, r *request) {
+	name := r.params["integration"]
+	i, found := s.integrations[name]
+	if !found {
+		notFound(w, "Integration", name)
+		return
+	}
+
+	if collections := s.integrationCollections(name); len(collections) > 0 {
+		badRequest(w, "Integration %s is used by collection %s", name,
+			path(collections[0].GetWorkspace(), collections[0].GetName()))
+		return
+	}
+
+	delete(s.integrations, name)
+	writeData(w, http.StatusOK, s.integrationData(i))
+}
+
+// integrationCollections returns the collections with a source which uses the integration.
+func (s *Server) integrationCollections(name string) []openapi.Collection {
+	paths := make([]string, 0)
+	for p, c := range s.collections {
+		for _, src := range c.Sources {
+			if src.GetIntegrationName() == name {
+				paths = append(paths, p)
+				break
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	collections := make([]openapi.Collection, 0, len(paths))
+	for _, p := range paths {
+		collections = append(collections, s.collections[p].data())
+	}
+
+	return collections
+}
+
+// integrationData returns the integration like the API does, which never returns secrets it can't obfuscate.
+func (s *Server) integrationData(i *integration) openapi.Integration {
+	data := i.Integration
+	data.Collections = s.integrationCollections(i.Name)
+
+	if i.Kafka != nil {
+		kafka := *i.Kafka
+		if !kafka.GetUseV3() {
+			status := make(map[string]openapi.StatusKafka)
+			for _, topic := range kafka.KafkaTopicNames {
+				status[topic] = openapi.StatusKafka{State: openapi.PtrString(i.topics.current())}
+			}
+			kafka.SourceStatusByTopic = &status
+		}
+		data.Kafka = &kafka
+	}
+	if i.Mongodb != nil {
+		mongo := *i.Mongodb
+		mongo.ConnectionUri = ""
+		data.Mongodb = &mongo
+	}
+	if i.Gcs != nil && i.Gcs.GcpServiceAccount != nil {
+		data.Gcs = &openapi.GcsIntegration{GcpServiceAccount: &openapi.GcpServiceAccount{}}
+	}
+
+	return data
+}
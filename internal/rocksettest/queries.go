@@ -0,0 +1,103 @@
+package rocksettest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+var (
+	statementRe = regexp.MustCompile(`(?i)^\s*(SELECT|WITH)\b`)
+	parameterRe = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+	relationRe  = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+([A-Za-z_][A-Za-z0-9_-]*(?:\.[A-Za-z0-9_-]+)?)(\s*\()?`)
+	withRe      = regexp.MustCompile(`(?i)\b([A-Za-z_][A-Za-z0-9_]*)\s+AS\s*\(`)
+)
+
+// validateQuery only understands enough SQL to reject statements which aren't queries, parameters without a value
+// and references to collections, views or aliases which don't exist, the way Rockset reports those errors.
+// Relations without a workspace are in the commons workspace, so _input, which only exists while ingesting, is
+// rejected too.
+func (s *Server) validateQuery(w http.ResponseWriter, r *request) {
+	var req openapi.QueryRequest
+	if !r.decode(w, &req) {
+		return
+	}
+	sql := req.Sql.Query
+
+	if !statementRe.MatchString(sql) {
+		queryError(w, sql, 0, "syntax error: expected SELECT or WITH")
+		return
+	}
+
+	bound := make(map[string]bool)
+	for _, p := range req.Sql.Parameters {
+		bound[p.Name] = true
+	}
+
+	parameters := make([]string, 0)
+	for _, m := range parameterRe.FindAllStringSubmatchIndex(sql, -1) {
+		name := sql[m[2]:m[3]]
+		if !bound[name] {
+			queryError(w, sql, m[0], fmt.Sprintf("no value given for parameter :%s", name))
+			return
+		}
+		parameters = append(parameters, name)
+	}
+
+	with := make(map[string]bool)
+	for _, m := range withRe.FindAllStringSubmatch(sql, -1) {
+		with[m[1]] = true
+	}
+
+	collections := make([]string, 0)
+	for _, m := range relationRe.FindAllStringSubmatchIndex(sql, -1) {
+		p := sql[m[2]:m[3]]
+		if m[4] >= 0 || with[p] {
+			// a table function, or the name of a WITH query
+			continue
+		}
+		if !strings.Contains(p, ".") {
+			p = path("commons", p)
+		}
+		if !s.relationExists(p) {
+			queryError(w, sql, m[2], fmt.Sprintf("Collection '%s' not found", p))
+			return
+		}
+		collections = append(collections, p)
+	}
+
+	writeJSON(w, http.StatusOK, openapi.ValidateQueryResponse{
+		Collections: collections,
+		Parameters:  parameters,
+	})
+}
+
+func (s *Server) relationExists(p string) bool {
+	if c, found := s.collections[p]; found && !c.deleted {
+		return true
+	}
+	if v, found := s.views[p]; found && !v.deleted {
+		return true
+	}
+	a, found := s.aliases[p]
+
+	return found && !a.deleted
+}
+
+// queryError responds with an error at the offset of the SQL, with the line and column Rockset reports.
+func queryError(w http.ResponseWriter, sql string, offset int, message string) {
+	before := sql[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := offset - strings.LastIndex(before, "\n")
+
+	writeJSON(w, http.StatusBadRequest, openapi.ErrorModel{
+		Type:    openapi.PtrString("QUERY_ERROR"),
+		Message: openapi.PtrString(message),
+		Line:    openapi.PtrInt32(int32(line)),
+		Column:  openapi.PtrInt32(int32(column)),
+		TraceId: openapi.PtrString(newID()),
+	})
+}
@@ -0,0 +1,320 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+// latestTag is the tag the API maintains for the latest version of every query lambda.
+const latestTag = "latest"
+
+type queryLambda struct {
+	workspace string
+	name      string
+	// versions in the order they were created
+	versions []openapi.QueryLambdaVersion
+	// tags maps tag names to versions
+	tags map[string]string
+}
+
+func (s *Server) createQueryLambda(w http.ResponseWriter, r *request) {
+	ws := r.params["workspace"]
+	var req openapi.CreateQueryLambdaRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid query lambda name %s", req.Name)
+		return
+	}
+	if !s.activeWorkspace(w, ws) {
+		return
+	}
+	if _, found := s.queryLambdas[path(ws, req.Name)]; found {
+		alreadyExists(w, "Query Lambda", path(ws, req.Name))
+		return
+	}
+	if req.Sql.Query == "" {
+		badRequest(w, "the query of query lambda %s is empty", path(ws, req.Name))
+		return
+	}
+
+	ql := &queryLambda{workspace: ws, name: req.Name, tags: make(map[string]string)}
+	s.queryLambdas[path(ws, req.Name)] = ql
+
+	writeData(w, http.StatusOK, ql.addVersion(r.user, req.Description, req.Sql))
+}
+
+// updateQueryLambda creates a new version of the query lambda, or the query lambda if it doesn't exist
+// and the create parameter is set.
+func (s *Server) updateQueryLambda(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["queryLambda"]
+	var req openapi.UpdateQueryLambdaRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	ql, found := s.queryLambdas[path(ws, name)]
+	if !found {
+		if r.URL.Query().Get("create") != "true" {
+			notFound(w, "Query Lambda", path(ws, name))
+			return
+		}
+		if !s.activeWorkspace(w, ws) {
+			return
+		}
+		ql = &queryLambda{workspace: ws, name: name, tags: make(map[string]string)}
+		s.queryLambdas[path(ws, name)] = ql
+	}
+
+	if req.Sql == nil || req.Sql.Query == "" {
+		badRequest(w, "the query of query lambda %s is empty", path(ws, name))
+		return
+	}
+
+	writeData(w, http.StatusOK, ql.addVersion(r.user, req.Description, *req.Sql))
+}
+
+func (ql *queryLambda) addVersion(user string, description *string, sql openapi.QueryLambdaSql) openapi.QueryLambdaVersion {
+	version := openapi.QueryLambdaVersion{
+		Name:        openapi.PtrString(ql.name),
+		Workspace:   openapi.PtrString(ql.workspace),
+		Version:     openapi.PtrString(strings.ReplaceAll(newID(), "-", "")[:16]),
+		Description: description,
+		Sql:         &sql,
+		State:       openapi.PtrString("ACTIVE"),
+		CreatedAt:   openapi.PtrString(timestamp()),
+		CreatedBy:   openapi.PtrString(user),
+		Collections: []string{},
+		Stats:       &openapi.QueryLambdaStats{},
+	}
+	if version.Sql.DefaultParameters == nil {
+		version.Sql.DefaultParameters = []openapi.QueryParameter{}
+	}
+
+	ql.versions = append(ql.versions, version)
+	ql.tags[latestTag] = version.GetVersion()
+
+	return version
+}
+
+func (s *Server) listQueryLambdas(w http.ResponseWriter, r *request) {
+	ws, inWorkspace := r.params["workspace"]
+	if inWorkspace && !s.activeWorkspace(w, ws) {
+		return
+	}
+
+	paths := make([]string, 0, len(s.queryLambdas))
+	for p, ql := range s.queryLambdas {
+		if !inWorkspace || ql.workspace == ws {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	list := make([]openapi.QueryLambda, 0, len(paths))
+	for _, p := range paths {
+		list = append(list, s.queryLambdas[p].data())
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) deleteQueryLambda(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	for _, sl := range s.scheduledLambdas {
+		if sl.GetWorkspace() == ql.workspace && sl.GetQlName() == ql.name {
+			badRequest(w, "Query Lambda %s is used by scheduled lambda %s", path(ql.workspace, ql.name), sl.GetRrn())
+			return
+		}
+	}
+
+	delete(s.queryLambdas, path(ql.workspace, ql.name))
+	writeData(w, http.StatusOK, ql.data())
+}
+
+func (s *Server) listQueryLambdaVersions(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, ql.versions)
+}
+
+func (s *Server) getQueryLambdaVersion(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	v, found := ql.version(r.params["version"])
+	if !found {
+		notFound(w, "Query Lambda version", r.params["version"])
+		return
+	}
+
+	writeData(w, http.StatusOK, v)
+}
+
+func (s *Server) deleteQueryLambdaVersion(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	version := r.params["version"]
+	for i, v := range ql.versions {
+		if v.GetVersion() != version {
+			continue
+		}
+		if len(ql.versions) == 1 {
+			badRequest(w, "can't delete the only version of query lambda %s", path(ql.workspace, ql.name))
+			return
+		}
+
+		ql.versions = append(ql.versions[:i], ql.versions[i+1:]...)
+		for tag, tv := range ql.tags {
+			if tv == version {
+				delete(ql.tags, tag)
+			}
+		}
+		ql.tags[latestTag] = ql.versions[len(ql.versions)-1].GetVersion()
+
+		writeData(w, http.StatusOK, v)
+		return
+	}
+
+	notFound(w, "Query Lambda version", version)
+}
+
+func (s *Server) createQueryLambdaTag(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	var req openapi.CreateQueryLambdaTagRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.TagName == latestTag {
+		badRequest(w, "the tag %s is reserved", latestTag)
+		return
+	}
+	v, found := ql.version(req.Version)
+	if !found {
+		notFound(w, "Query Lambda version", req.Version)
+		return
+	}
+
+	ql.tags[req.TagName] = req.Version
+	writeData(w, http.StatusOK, openapi.QueryLambdaTag{TagName: openapi.PtrString(req.TagName), Version: &v})
+}
+
+func (s *Server) listQueryLambdaTags(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	names := make([]string, 0, len(ql.tags))
+	for name := range ql.tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]openapi.QueryLambdaTag, 0, len(names))
+	for _, name := range names {
+		list = append(list, ql.tag(name))
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) getQueryLambdaTag(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	tag := r.params["tag"]
+	if _, found := ql.tags[tag]; !found {
+		notFound(w, "Query Lambda tag", tag)
+		return
+	}
+
+	writeData(w, http.StatusOK, ql.tag(tag))
+}
+
+func (s *Server) deleteQueryLambdaTag(w http.ResponseWriter, r *request) {
+	ql, ok := s.queryLambda(w, r)
+	if !ok {
+		return
+	}
+
+	tag := r.params["tag"]
+	if _, found := ql.tags[tag]; !found {
+		notFound(w, "Query Lambda tag", tag)
+		return
+	}
+	if tag == latestTag {
+		badRequest(w, "the tag %s can't be deleted", latestTag)
+		return
+	}
+
+	data := ql.tag(tag)
+	delete(ql.tags, tag)
+
+	writeData(w, http.StatusOK, data)
+}
+
+// queryLambda returns the query lambda of the request, and writes an error response if it doesn't exist.
+func (s *Server) queryLambda(w http.ResponseWriter, r *request) (*queryLambda, bool) {
+	ws, name := r.params["workspace"], r.params["queryLambda"]
+	ql, found := s.queryLambdas[path(ws, name)]
+	if !found {
+		notFound(w, "Query Lambda", path(ws, name))
+		return nil, false
+	}
+
+	return ql, true
+}
+
+func (ql *queryLambda) version(version string) (openapi.QueryLambdaVersion, bool) {
+	for _, v := range ql.versions {
+		if v.GetVersion() == version {
+			return v, true
+		}
+	}
+
+	return openapi.QueryLambdaVersion{}, false
+}
+
+func (ql *queryLambda) tag(name string) openapi.QueryLambdaTag {
+	v, _ := ql.version(ql.tags[name])
+	return openapi.QueryLambdaTag{TagName: openapi.PtrString(name), Version: &v}
+}
+
+func (ql *queryLambda) data() openapi.QueryLambda {
+	latest := ql.versions[len(ql.versions)-1]
+
+	return openapi.QueryLambda{
+		Name:          openapi.PtrString(ql.name),
+		Workspace:     openapi.PtrString(ql.workspace),
+		LatestVersion: &latest,
+		VersionCount:  openapi.PtrInt32(int32(len(ql.versions))),
+		LastUpdated:   latest.CreatedAt,
+		LastUpdatedBy: latest.CreatedBy,
+		Collections:   latest.Collections,
+	}
+}
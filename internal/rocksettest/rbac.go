@@ -0,0 +1,229 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+func (s *Server) createRole(w http.ResponseWriter, r *request) {
+	var req openapi.CreateRoleRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	name := req.GetRoleName()
+	if !nameRe.MatchString(name) {
+		badRequest(w, "invalid role name %s", name)
+		return
+	}
+	if _, found := s.roles[name]; found {
+		alreadyExists(w, "Role", name)
+		return
+	}
+
+	role := &openapi.Role{
+		RoleName:    openapi.PtrString(name),
+		Description: req.Description,
+		Privileges:  req.Privileges,
+		CreatedAt:   openapi.PtrString(timestamp()),
+		CreatedBy:   openapi.PtrString(r.user),
+		OwnerEmail:  openapi.PtrString(r.user),
+	}
+	s.roles[name] = role
+
+	writeData(w, http.StatusOK, role)
+}
+
+func (s *Server) getRole(w http.ResponseWriter, r *request) {
+	name := r.params["roleName"]
+	role, found := s.roles[name]
+	if !found {
+		notFound(w, "Role", name)
+		return
+	}
+
+	writeData(w, http.StatusOK, role)
+}
+
+func (s *Server) listRoles(w http.ResponseWriter, _ *request) {
+	names := make([]string, 0, len(s.roles))
+	for name := range s.roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]openapi.Role, 0, len(names))
+	for _, name := range names {
+		list = append(list, *s.roles[name])
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateRole(w http.ResponseWriter, r *request) {
+	name := r.params["roleName"]
+	role, found := s.roles[name]
+	if !found {
+		notFound(w, "Role", name)
+		return
+	}
+
+	var req openapi.UpdateRoleRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	role.Description = req.Description
+	role.Privileges = req.Privileges
+
+	writeData(w, http.StatusOK, role)
+}
+
+func (s *Server) deleteRole(w http.ResponseWriter, r *request) {
+	name := r.params["roleName"]
+	role, found := s.roles[name]
+	if !found {
+		notFound(w, "Role", name)
+		return
+	}
+
+	for _, u := range s.users {
+		for _, ur := range u.Roles {
+			if ur == name {
+				badRequest(w, "Role %s is assigned to user %s", name, u.Email)
+				return
+			}
+		}
+	}
+
+	delete(s.roles, name)
+	writeData(w, http.StatusOK, role)
+}
+
+// createUser invites a user, who stays in the state NEW as the invitation never is accepted.
+func (s *Server) createUser(w http.ResponseWriter, r *request) {
+	var req openapi.CreateUserRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.Email == "" {
+		badRequest(w, "the email is empty")
+		return
+	}
+	if _, found := s.users[req.Email]; found {
+		alreadyExists(w, "User", req.Email)
+		return
+	}
+	if !s.rolesExist(w, req.Roles) {
+		return
+	}
+
+	u := &openapi.User{
+		Email:     req.Email,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Roles:     req.Roles,
+		State:     openapi.PtrString("NEW"),
+		CreatedAt: openapi.PtrString(timestamp()),
+	}
+	s.users[req.Email] = u
+
+	writeData(w, http.StatusOK, u)
+}
+
+// getUser returns the user, which unlike most other responses isn't wrapped in data.
+func (s *Server) getUser(w http.ResponseWriter, r *request) {
+	email := r.params["user"]
+	u, found := s.users[email]
+	if !found {
+		notFound(w, "User", email)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, u)
+}
+
+func (s *Server) getCurrentUser(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, s.users[r.user])
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, _ *request) {
+	emails := make([]string, 0, len(s.users))
+	for email := range s.users {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+
+	list := make([]openapi.User, 0, len(emails))
+	for _, email := range emails {
+		list = append(list, *s.users[email])
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *request) {
+	email := r.params["user"]
+	u, found := s.users[email]
+	if !found {
+		notFound(w, "User", email)
+		return
+	}
+
+	var req openapi.UpdateUserRequest
+	if !r.decode(w, &req) {
+		return
+	}
+	if req.Roles != nil && !s.rolesExist(w, req.Roles) {
+		return
+	}
+
+	if req.FirstName != nil {
+		u.FirstName = req.FirstName
+	}
+	if req.LastName != nil {
+		u.LastName = req.LastName
+	}
+	if req.Roles != nil {
+		u.Roles = req.Roles
+	}
+
+	writeJSON(w, http.StatusOK, u)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *request) {
+	email := r.params["user"]
+	u, found := s.users[email]
+	if !found {
+		notFound(w, "User", email)
+		return
+	}
+	if email == r.user {
+		badRequest(w, "users can't delete themselves")
+		return
+	}
+
+	delete(s.users, email)
+	for id, k := range s.apiKeys {
+		if k.user == email {
+			delete(s.apiKeys, id)
+		}
+	}
+
+	writeData(w, http.StatusOK, u)
+}
+
+// rolesExist checks that all roles exist, and writes an error response if they don't.
+func (s *Server) rolesExist(w http.ResponseWriter, roles []string) bool {
+	for _, role := range roles {
+		if _, found := s.roles[role]; !found {
+			notFound(w, "Role", role)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,119 @@
+package rocksettest
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// nameRe matches the names the API accepts for workspaces, collections and most other resources.
+var nameRe = regexp.MustCompile(`^[[:alnum:]][[:alnum:]_-]*$`)
+
+const orgPath = "/v1/orgs/self"
+
+func (s *Server) registerRoutes() {
+	s.handle(http.MethodGet, orgPath, s.getOrganization)
+
+	s.handle(http.MethodPost, orgPath+"/ws", s.createWorkspace)
+	s.handle(http.MethodGet, orgPath+"/ws", s.listWorkspaces)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}", s.getWorkspace)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}", s.deleteWorkspace)
+
+	s.handle(http.MethodGet, orgPath+"/collections", s.listCollections)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/collections", s.createCollection)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/collections", s.listCollections)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/collections/{collection}", s.getCollection)
+	s.handle(http.MethodPut, orgPath+"/ws/{workspace}/collections/{collection}", s.updateCollection)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/collections/{collection}", s.deleteCollection)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/collections/{collection}/docs", s.addDocuments)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/collections/{collection}/docs", s.deleteDocuments)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/collections/{collection}/offsets/commit", s.commitOffsets)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/collections/{collection}/sources", s.createSource)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/collections/{collection}/sources", s.listCollectionSources)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/collections/{collection}/sources/{source}", s.getSource)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/collections/{collection}/sources/{source}", s.deleteSource)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/collections/{collection}/sources/{source}/suspend",
+		s.suspendSource)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/collections/{collection}/sources/{source}/resume",
+		s.resumeSource)
+
+	s.handle(http.MethodGet, orgPath+"/aliases", s.listAliases)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/aliases", s.createAlias)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/aliases", s.listAliases)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/aliases/{alias}", s.getAlias)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/aliases/{alias}", s.updateAlias)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/aliases/{alias}", s.deleteAlias)
+
+	s.handle(http.MethodGet, orgPath+"/views", s.listViews)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/views", s.createView)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/views", s.listViews)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/views/{view}", s.getView)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/views/{view}", s.updateView)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/views/{view}", s.deleteView)
+
+	s.handle(http.MethodPost, orgPath+"/queries/validations", s.validateQuery)
+
+	s.handle(http.MethodGet, orgPath+"/lambdas", s.listQueryLambdas)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/lambdas", s.createQueryLambda)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/lambdas", s.listQueryLambdas)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/lambdas/{queryLambda}", s.deleteQueryLambda)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/versions", s.updateQueryLambda)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/versions", s.listQueryLambdaVersions)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/versions/{version}",
+		s.getQueryLambdaVersion)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/versions/{version}",
+		s.deleteQueryLambdaVersion)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/tags", s.createQueryLambdaTag)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/tags", s.listQueryLambdaTags)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/tags/{tag}", s.getQueryLambdaTag)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/lambdas/{queryLambda}/tags/{tag}", s.deleteQueryLambdaTag)
+
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/scheduled_lambdas", s.createScheduledLambda)
+	s.handle(http.MethodGet, orgPath+"/ws/{workspace}/scheduled_lambdas/{scheduledLambdaId}", s.getScheduledLambda)
+	s.handle(http.MethodPost, orgPath+"/ws/{workspace}/scheduled_lambdas/{scheduledLambdaId}",
+		s.updateScheduledLambda)
+	s.handle(http.MethodDelete, orgPath+"/ws/{workspace}/scheduled_lambdas/{scheduledLambdaId}",
+		s.deleteScheduledLambda)
+
+	s.handle(http.MethodPost, orgPath+"/roles", s.createRole)
+	s.handle(http.MethodGet, orgPath+"/roles", s.listRoles)
+	s.handle(http.MethodGet, orgPath+"/roles/{roleName}", s.getRole)
+	s.handle(http.MethodPost, orgPath+"/roles/{roleName}", s.updateRole)
+	s.handle(http.MethodDelete, orgPath+"/roles/{roleName}", s.deleteRole)
+
+	s.handle(http.MethodPost, orgPath+"/users", s.createUser)
+	s.handle(http.MethodGet, orgPath+"/users", s.listUsers)
+	// self must be registered before {user}, as routes are matched in order
+	s.handle(http.MethodGet, orgPath+"/users/self", s.getCurrentUser)
+	s.handle(http.MethodGet, orgPath+"/users/{user}", s.getUser)
+	s.handle(http.MethodPost, orgPath+"/users/{user}", s.updateUser)
+	s.handle(http.MethodDelete, orgPath+"/users/{user}", s.deleteUser)
+
+	s.handle(http.MethodPost, orgPath+"/users/self/apikeys", s.createAPIKey)
+	s.handle(http.MethodGet, orgPath+"/users/{user}/apikeys", s.listAPIKeys)
+	s.handle(http.MethodGet, orgPath+"/users/{user}/apikeys/{name}", s.getAPIKey)
+	s.handle(http.MethodPost, orgPath+"/users/{user}/apikeys/{name}", s.updateAPIKey)
+	s.handle(http.MethodDelete, orgPath+"/users/{user}/apikeys/{name}", s.deleteAPIKey)
+
+	s.handle(http.MethodPost, orgPath+"/integrations", s.createIntegration)
+	s.handle(http.MethodGet, orgPath+"/integrations", s.listIntegrations)
+	s.handle(http.MethodGet, orgPath+"/integrations/{integration}", s.getIntegration)
+	s.handle(http.MethodPut, orgPath+"/integrations/{integration}", s.updateIntegration)
+	s.handle(http.MethodDelete, orgPath+"/integrations/{integration}", s.deleteIntegration)
+
+	s.handle(http.MethodPost, orgPath+"/virtualinstances", s.createVirtualInstance)
+	s.handle(http.MethodGet, orgPath+"/virtualinstances", s.listVirtualInstances)
+	s.handle(http.MethodGet, orgPath+"/virtualinstances/{virtualInstanceId}", s.getVirtualInstance)
+	s.handle(http.MethodPost, orgPath+"/virtualinstances/{virtualInstanceId}", s.updateVirtualInstance)
+	s.handle(http.MethodDelete, orgPath+"/virtualinstances/{virtualInstanceId}", s.deleteVirtualInstance)
+	s.handle(http.MethodPost, orgPath+"/virtualinstances/{virtualInstanceId}/suspend", s.suspendVirtualInstance)
+	s.handle(http.MethodPost, orgPath+"/virtualinstances/{virtualInstanceId}/resume", s.resumeVirtualInstance)
+	s.handle(http.MethodPost, orgPath+"/virtualinstances/{virtualInstanceId}/mounts", s.mountCollections)
+	s.handle(http.MethodGet, orgPath+"/virtualinstances/{virtualInstanceId}/mounts", s.listMounts)
+	s.handle(http.MethodGet, orgPath+"/virtualinstances/{virtualInstanceId}/mounts/{collectionPath}", s.getMount)
+	s.handle(http.MethodDelete, orgPath+"/virtualinstances/{virtualInstanceId}/mounts/{collectionPath}",
+		s.unmountCollection)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, _ *request) {
+	writeData(w, http.StatusOK, s.org)
+}
@@ -0,0 +1,113 @@
+package rocksettest
+
+import (
+	"net/http"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type scheduledLambda struct {
+	openapi.ScheduledLambda
+}
+
+func (s *Server) createScheduledLambda(w http.ResponseWriter, r *request) {
+	ws := r.params["workspace"]
+	var req openapi.CreateScheduledLambdaRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !s.activeWorkspace(w, ws) {
+		return
+	}
+	if _, found := s.queryLambdas[path(ws, req.QlName)]; !found {
+		notFound(w, "Query Lambda", path(ws, req.QlName))
+		return
+	}
+	if req.CronString == "" {
+		badRequest(w, "the cron string is empty")
+		return
+	}
+	if req.Tag != nil && req.Version != nil {
+		badRequest(w, "only one of tag and version can be set")
+		return
+	}
+
+	id := rrn("sl", newID())
+	sl := &scheduledLambda{ScheduledLambda: openapi.ScheduledLambda{
+		Rrn:                    openapi.PtrString(id),
+		Workspace:              openapi.PtrString(ws),
+		QlName:                 openapi.PtrString(req.QlName),
+		CronString:             openapi.PtrString(req.CronString),
+		Tag:                    req.Tag,
+		Version:                req.Version,
+		TotalTimesToExecute:    req.TotalTimesToExecute,
+		WebhookUrl:             req.WebhookUrl,
+		WebhookPayload:         req.WebhookPayload,
+		ExecutionCount:         openapi.PtrInt64(0),
+		ResumePermanentError:   openapi.PtrBool(false),
+		QueryExecutionStatus:   &openapi.ExecutionStatus{State: openapi.PtrString("NO_ERRORS_YET")},
+		WebhookExecutionStatus: &openapi.ExecutionStatus{State: openapi.PtrString("NO_ERRORS_YET")},
+	}}
+	s.scheduledLambdas[id] = sl
+
+	writeData(w, http.StatusOK, sl.ScheduledLambda)
+}
+
+func (s *Server) getScheduledLambda(w http.ResponseWriter, r *request) {
+	sl, ok := s.scheduledLambda(w, r)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, sl.ScheduledLambda)
+}
+
+func (s *Server) updateScheduledLambda(w http.ResponseWriter, r *request) {
+	sl, ok := s.scheduledLambda(w, r)
+	if !ok {
+		return
+	}
+
+	var req openapi.UpdateScheduledLambdaRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.TotalTimesToExecute != nil {
+		sl.TotalTimesToExecute = req.TotalTimesToExecute
+	}
+	if req.WebhookUrl != nil {
+		sl.WebhookUrl = req.WebhookUrl
+	}
+	if req.WebhookPayload != nil {
+		sl.WebhookPayload = req.WebhookPayload
+	}
+	if req.ResumePermanentError != nil {
+		sl.ResumePermanentError = req.ResumePermanentError
+	}
+
+	writeData(w, http.StatusOK, sl.ScheduledLambda)
+}
+
+func (s *Server) deleteScheduledLambda(w http.ResponseWriter, r *request) {
+	sl, ok := s.scheduledLambda(w, r)
+	if !ok {
+		return
+	}
+
+	delete(s.scheduledLambdas, sl.GetRrn())
+	writeData(w, http.StatusOK, sl.ScheduledLambda)
+}
+
+// scheduledLambda returns the scheduled lambda of the request, and writes an error response if it doesn't exist.
+func (s *Server) scheduledLambda(w http.ResponseWriter, r *request) (*scheduledLambda, bool) {
+	ws, id := r.params["workspace"], r.params["scheduledLambdaId"]
+	sl, found := s.scheduledLambdas[id]
+	if !found || sl.GetWorkspace() != ws {
+		notFound(w, "Scheduled Lambda", id)
+		return nil, false
+	}
+
+	return sl, true
+}
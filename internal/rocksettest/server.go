@@ -0,0 +1,448 @@
+// Package rocksettest provides an in-process fake of the Rockset REST API, which lets the provider be tested
+// without a Rockset organization.
+//
+// The fake keeps its state in memory, and simulates the asynchronous state transitions of the real API: a resource
+// which is created, updated or deleted moves to its next state every time it is read, so the wait loops of the
+// provider run like they do against the real API, just faster.
+//
+//	srv := rocksettest.NewServer()
+//	defer srv.Close()
+//
+//	rc, err := rockset.NewClient(rockset.WithAPIServer(srv.URL), rockset.WithAPIKey(rocksettest.APIKey),
+//		rockset.WithHTTPClient(srv.Client()))
+package rocksettest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+const (
+	// APIKey is the API key of the organization admin, which is accepted by the fake in addition to the
+	// API keys created using it.
+	APIKey = "rocksettest-api-key"
+	// AdminEmail is the email of the user who owns the APIKey.
+	AdminEmail = "admin@rocksettest.invalid"
+	// OrganizationID is the ID of the organization of the fake.
+	OrganizationID = "rocksettest"
+	// Region is the region the fake pretends to run in, which is part of the RRNs.
+	Region = "usw2a1"
+)
+
+// Server is a fake Rockset API server. It serves HTTPS, as the Rockset client only supports HTTPS, so the client
+// must use the http.Client of the server, which trusts its certificate. Its URL is used as the API server.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	routes []route
+	org    openapi.Organization
+
+	workspaces       map[string]*workspace
+	collections      map[string]*collection
+	aliases          map[string]*alias
+	views            map[string]*view
+	queryLambdas     map[string]*queryLambda
+	scheduledLambdas map[string]*scheduledLambda
+	roles            map[string]*openapi.Role
+	users            map[string]*openapi.User
+	apiKeys          map[string]*apiKey
+	integrations     map[string]*integration
+	virtualInstances map[string]*virtualInstance
+	mounts           map[string]*mount
+}
+
+// Option configures the Server.
+type Option func(*Server)
+
+// WithDefaultVirtualInstanceID sets the ID of the default virtual instance of the organization, which otherwise
+// is random.
+func WithDefaultVirtualInstanceID(id string) Option {
+	return func(s *Server) {
+		vi := s.virtualInstances[s.defaultVirtualInstanceID()]
+		delete(s.virtualInstances, vi.GetId())
+		vi.Id = &id
+		vi.Rrn = openapi.PtrString(rrn("vi", id))
+		s.virtualInstances[id] = vi
+	}
+}
+
+// NewServer starts a new fake Rockset API server, which must be closed when it no longer is needed.
+func NewServer(options ...Option) *Server {
+	s := &Server{
+		workspaces:       make(map[string]*workspace),
+		collections:      make(map[string]*collection),
+		aliases:          make(map[string]*alias),
+		views:            make(map[string]*view),
+		queryLambdas:     make(map[string]*queryLambda),
+		scheduledLambdas: make(map[string]*scheduledLambda),
+		roles:            make(map[string]*openapi.Role),
+		users:            make(map[string]*openapi.User),
+		apiKeys:          make(map[string]*apiKey),
+		integrations:     make(map[string]*integration),
+		virtualInstances: make(map[string]*virtualInstance),
+		mounts:           make(map[string]*mount),
+	}
+	s.seed()
+	s.registerRoutes()
+
+	for _, o := range options {
+		o(s)
+	}
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	s.org.Clusters = []openapi.Cluster{{
+		ApiserverUrl:   openapi.PtrString(s.URL),
+		AwsRegion:      openapi.PtrString("us-west-2"),
+		ClusterType:    openapi.PtrString("PUBLIC"),
+		Domain:         openapi.PtrString("rocksettest.invalid"),
+		TopLevelDomain: openapi.PtrString("invalid"),
+	}}
+
+	return s
+}
+
+// seed creates the resources every Rockset organization has: the built-in roles, the admin user,
+// the default virtual instance and the commons workspace with the _events collection.
+func (s *Server) seed() {
+	now := timestamp()
+
+	s.org = openapi.Organization{
+		Id:          openapi.PtrString(OrganizationID),
+		DisplayName: openapi.PtrString("Rockset Test"),
+		CreatedAt:   openapi.PtrString(now),
+		RocksetUser: openapi.PtrString("rocksettest"),
+	}
+
+	for _, name := range []string{"admin", "member", "read-only"} {
+		s.roles[name] = &openapi.Role{
+			RoleName:    openapi.PtrString(name),
+			Description: openapi.PtrString("built-in " + name + " role"),
+			CreatedAt:   openapi.PtrString(now),
+		}
+	}
+
+	s.users[AdminEmail] = &openapi.User{
+		Email:     AdminEmail,
+		FirstName: openapi.PtrString("Admin"),
+		LastName:  openapi.PtrString("User"),
+		Roles:     []string{"admin"},
+		State:     openapi.PtrString("ACTIVE"),
+		CreatedAt: openapi.PtrString(now),
+	}
+	s.apiKeys[apiKeyID(AdminEmail, "admin")] = &apiKey{
+		user: AdminEmail,
+		key: openapi.ApiKey{
+			Name:      "admin",
+			Key:       APIKey,
+			CreatedBy: openapi.PtrString(AdminEmail),
+			CreatedAt: openapi.PtrString(now),
+			State:     openapi.PtrString("ACTIVE"),
+		},
+	}
+
+	id := newID()
+	s.virtualInstances[id] = &virtualInstance{
+		VirtualInstance: openapi.VirtualInstance{
+			Id:                    openapi.PtrString(id),
+			Rrn:                   openapi.PtrString(rrn("vi", id)),
+			Name:                  "main",
+			Description:           openapi.PtrString("default virtual instance"),
+			DefaultVi:             openapi.PtrBool(true),
+			CurrentSize:           openapi.PtrString("SMALL"),
+			DesiredSize:           openapi.PtrString("SMALL"),
+			MonitoringEnabled:     openapi.PtrBool(false),
+			EnableRemountOnResume: openapi.PtrBool(false),
+			AutoSuspendSeconds:    openapi.PtrInt32(0),
+			CreatedAt:             openapi.PtrString(now),
+			CreatedBy:             openapi.PtrString(AdminEmail),
+		},
+		state: newLifecycle("ACTIVE"),
+	}
+
+	s.workspaces["commons"] = &workspace{Workspace: openapi.Workspace{
+		Name:        openapi.PtrString("commons"),
+		Description: openapi.PtrString("Commons workspace"),
+		CreatedAt:   openapi.PtrString(now),
+		CreatedBy:   openapi.PtrString(AdminEmail),
+	}}
+	s.collections[path("commons", "_events")] = &collection{
+		Collection: openapi.Collection{
+			Name:                   openapi.PtrString("_events"),
+			Workspace:              openapi.PtrString("commons"),
+			Description:            openapi.PtrString("Rockset events collection"),
+			Rrn:                    openapi.PtrString(rrn("collection", newID())),
+			CreatedAt:              openapi.PtrString(now),
+			CreatedBy:              openapi.PtrString(AdminEmail),
+			StorageCompressionType: openapi.PtrString("LZ4"),
+			Sources:                []openapi.Source{},
+			InsertOnly:             openapi.PtrBool(false),
+			ReadOnly:               openapi.PtrBool(true),
+			Stats:                  &openapi.CollectionStats{DocCount: openapi.PtrInt64(0)},
+		},
+		status: newLifecycle("READY"),
+	}
+}
+
+func (s *Server) defaultVirtualInstanceID() string {
+	for id, vi := range s.virtualInstances {
+		if vi.GetDefaultVi() {
+			return id
+		}
+	}
+
+	return ""
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.authenticate(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "AUTHEXCEPTION", "invalid API key")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i, segment := range segments {
+		if s, err := url.PathUnescape(segment); err == nil {
+			segments[i] = s
+		}
+	}
+
+	for _, rt := range s.routes {
+		params, ok := rt.match(r.Method, segments)
+		if !ok {
+			continue
+		}
+
+		rt.handler(w, &request{Request: r, params: params, user: user})
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "NOTFOUND", fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+}
+
+// authenticate returns the email of the user who owns the API key of the request.
+func (s *Server) authenticate(r *http.Request) (string, bool) {
+	scheme, key, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !strings.EqualFold(scheme, "apikey") {
+		return "", false
+	}
+
+	for _, k := range s.apiKeys {
+		if k.key.Key == key && k.key.GetState() == "ACTIVE" {
+			return k.user, true
+		}
+	}
+
+	return "", false
+}
+
+type handlerFunc func(http.ResponseWriter, *request)
+
+type request struct {
+	*http.Request
+	params map[string]string
+	// user is the email of the user which made the request
+	user string
+}
+
+// decode decodes the JSON body of the request into v, and writes an error response if it fails.
+func (r *request) decode(w http.ResponseWriter, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALIDINPUT", fmt.Sprintf("failed to parse request body: %v", err))
+		return false
+	}
+
+	return true
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+// handle registers the handler for the method and path, where path segments like {workspace} match any value.
+// Routes are matched in the order they are registered.
+func (s *Server) handle(method, path string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (rt route) match(method string, segments []string) (map[string]string, bool) {
+	if method != rt.method || len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, s := range rt.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			params[strings.Trim(s, "{}")] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// writeData writes v as the data of the response, which is how the API wraps all responses.
+func writeData(w http.ResponseWriter, status int, v interface{}) {
+	writeJSON(w, status, map[string]interface{}{"data": v})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(plain(reflect.ValueOf(v)))
+}
+
+// plain converts v to maps, slices and scalars which encoding/json marshals using only the struct tags. The openapi
+// models implement json.Marshaler to leave out read-only fields like the current size of a virtual instance,
+// as the client never sends them, but the fake must return them like the API does.
+func plain(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return plain(v.Elem())
+	case reflect.Struct:
+		m := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if strings.Contains(opts, "omitempty") && empty(v.Field(i)) {
+				continue
+			}
+			m[name] = plain(v.Field(i))
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = plain(v.Index(i))
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = plain(iter.Value())
+		}
+		return m
+	default:
+		return v.Interface()
+	}
+}
+
+// empty reports if encoding/json considers the value empty for omitempty.
+func empty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, errorType, message string) {
+	writeJSON(w, status, openapi.ErrorModel{
+		Type:    openapi.PtrString(errorType),
+		Message: openapi.PtrString(message),
+		TraceId: openapi.PtrString(newID()),
+	})
+}
+
+func notFound(w http.ResponseWriter, kind, name string) {
+	writeError(w, http.StatusNotFound, "NOTFOUND", fmt.Sprintf("%s %s not found", kind, name))
+}
+
+func alreadyExists(w http.ResponseWriter, kind, name string) {
+	writeError(w, http.StatusConflict, "ALREADYEXISTS", fmt.Sprintf("%s %s already exists", kind, name))
+}
+
+func badRequest(w http.ResponseWriter, format string, args ...interface{}) {
+	writeError(w, http.StatusBadRequest, "INVALIDINPUT", fmt.Sprintf(format, args...))
+}
+
+// lifecycle is the state of a resource which changes asynchronously. It moves to the next state every time the
+// resource is read, and then stays in the last state.
+type lifecycle struct {
+	states []string
+}
+
+func newLifecycle(states ...string) lifecycle {
+	return lifecycle{states: states}
+}
+
+// next moves to the next state, and returns it.
+func (l *lifecycle) next() string {
+	if len(l.states) > 1 {
+		l.states = l.states[1:]
+	}
+
+	return l.states[0]
+}
+
+func (l *lifecycle) current() string {
+	return l.states[0]
+}
+
+func (l *lifecycle) done() bool {
+	return len(l.states) == 1
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// newID returns a random UUID.
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	h := hex.EncodeToString(b)
+
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:])
+}
+
+func rrn(kind, id string) string {
+	return fmt.Sprintf("rrn:%s:%s:%s", kind, Region, id)
+}
+
+func path(workspace, name string) string {
+	return workspace + "." + name
+}
@@ -0,0 +1,220 @@
+package rocksettest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_Authentication(t *testing.T) {
+	ctx := context.TODO()
+	srv := NewServer()
+	defer srv.Close()
+
+	_, err := NewClient(t, srv, rockset.WithAPIKey("invalid")).GetOrganization(ctx)
+	var re rockerr.Error
+	require.True(t, errors.As(err, &re))
+	assert.Equal(t, 401, re.StatusCode)
+
+	rc := NewClient(t, srv)
+	org, err := rc.GetOrganization(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, OrganizationID, org.GetId())
+	assert.Equal(t, srv.URL, org.Clusters[0].GetApiserverUrl())
+
+	key, err := rc.CreateAPIKey(ctx, "test")
+	require.NoError(t, err)
+	user, err := NewClient(t, srv, rockset.WithAPIKey(key.Key)).GetCurrentUser(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, AdminEmail, user.Email)
+
+	_, err = rc.UpdateAPIKey(ctx, "test", option.State(option.KeySuspended))
+	require.NoError(t, err)
+	_, err = NewClient(t, srv, rockset.WithAPIKey(key.Key)).GetCurrentUser(ctx)
+	assert.Error(t, err)
+}
+
+func TestServer_Collection(t *testing.T) {
+	ctx := context.TODO()
+	srv := NewServer()
+	defer srv.Close()
+	rc := NewClient(t, srv)
+
+	_, err := rc.CreateWorkspace(ctx, "test")
+	require.NoError(t, err)
+
+	_, err = rc.CreateCollection(ctx, "test", "events")
+	require.NoError(t, err)
+	require.NoError(t, rc.Wait.UntilCollectionReady(ctx, "test", "events"))
+
+	_, err = rc.CreateAlias(ctx, "test", "latest", []string{"test.events"})
+	require.NoError(t, err)
+
+	err = rc.DeleteCollection(ctx, "test", "events")
+	assert.ErrorContains(t, err, "referenced by alias test.latest")
+
+	require.NoError(t, rc.DeleteAlias(ctx, "test", "latest"))
+	require.NoError(t, rc.Wait.UntilAliasGone(ctx, "test", "latest"))
+
+	err = rc.DeleteWorkspace(ctx, "test")
+	assert.ErrorContains(t, err, "not empty")
+
+	require.NoError(t, rc.DeleteCollection(ctx, "test", "events"))
+	require.NoError(t, rc.Wait.UntilCollectionGone(ctx, "test", "events"))
+
+	_, err = rc.GetCollection(ctx, "test", "events")
+	var re rockerr.Error
+	require.True(t, errors.As(err, &re))
+	assert.True(t, re.IsNotFoundError())
+
+	require.NoError(t, rc.DeleteWorkspace(ctx, "test"))
+	require.NoError(t, rc.Wait.UntilWorkspaceGone(ctx, "test"))
+}
+
+func TestServer_Source(t *testing.T) {
+	ctx := context.TODO()
+	srv := NewServer()
+	defer srv.Close()
+	rc := NewClient(t, srv)
+
+	_, err := rc.CreateCollection(ctx, "commons", "events")
+	require.NoError(t, err)
+
+	created, _, err := rc.SourcesApi.CreateSource(ctx, "commons", "events").Body(openapi.Source{
+		Kinesis: &openapi.SourceKinesis{StreamName: "clicks"},
+	}).Execute()
+	require.NoError(t, err)
+	id := created.Data.GetId()
+
+	listed, _, err := rc.SourcesApi.ListCollectionSources(ctx, "commons", "events").Execute()
+	require.NoError(t, err)
+	require.Len(t, listed.Data, 1)
+	assert.Equal(t, "clicks", listed.Data[0].Kinesis.StreamName)
+
+	suspended, _, err := rc.SourcesApi.SuspendSource(ctx, "commons", "events", id).
+		Body(openapi.SuspendSourceRequest{ResumeAfterDuration: openapi.PtrString("30m")}).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "SUSPENDED", suspended.Data.Status.GetState())
+	assert.NotEmpty(t, suspended.Data.GetResumeAt())
+
+	resumed, _, err := rc.SourcesApi.ResumeSource(ctx, "commons", "events", id).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "WATCHING", resumed.Data.Status.GetState())
+	assert.Empty(t, resumed.Data.GetSuspendedAt())
+
+	// once resume_after has passed, the source is resumed automatically
+	_, _, err = rc.SourcesApi.SuspendSource(ctx, "commons", "events", id).
+		Body(openapi.SuspendSourceRequest{ResumeAfterDuration: openapi.PtrString("1s")}).Execute()
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	got, _, err := rc.SourcesApi.GetSource(ctx, "commons", "events", id).Execute()
+	require.NoError(t, err)
+	assert.Equal(t, "WATCHING", got.Data.Status.GetState())
+	assert.Empty(t, got.Data.GetResumeAt())
+
+	_, _, err = rc.SourcesApi.DeleteSource(ctx, "commons", "events", id).Execute()
+	require.NoError(t, err)
+
+	_, resp, err := rc.SourcesApi.GetSource(ctx, "commons", "events", id).Execute()
+	require.Error(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	_, _, err = rc.SourcesApi.CreateSource(ctx, "commons", "events").Body(openapi.Source{
+		IntegrationName: openapi.PtrString("missing"),
+		S3:              &openapi.SourceS3{Bucket: "bucket"},
+	}).Execute()
+	assert.Error(t, err)
+}
+
+func TestServer_QueryLambda(t *testing.T) {
+	ctx := context.TODO()
+	srv := NewServer()
+	defer srv.Close()
+	rc := NewClient(t, srv)
+
+	v1, err := rc.CreateQueryLambda(ctx, "commons", "ql", "SELECT 1")
+	require.NoError(t, err)
+	_, err = rc.CreateQueryLambdaTag(ctx, "commons", "ql", v1.GetVersion(), "stable")
+	require.NoError(t, err)
+
+	v2, err := rc.UpdateQueryLambda(ctx, "commons", "ql", "SELECT 2")
+	require.NoError(t, err)
+	assert.NotEqual(t, v1.GetVersion(), v2.GetVersion())
+
+	tag, err := rc.GetQueryLambdaVersionByTag(ctx, "commons", "ql", "stable")
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT 1", tag.Version.Sql.Query)
+
+	latest, err := rc.GetQueryLambdaVersionByTag(ctx, "commons", "ql", "latest")
+	require.NoError(t, err)
+	assert.Equal(t, v2.GetVersion(), latest.Version.GetVersion())
+}
+
+func TestServer_VirtualInstance(t *testing.T) {
+	ctx := context.TODO()
+	srv := NewServer(WithDefaultVirtualInstanceID("default"))
+	defer srv.Close()
+	rc := NewClient(t, srv)
+
+	_, err := rc.DeleteVirtualInstance(ctx, "default")
+	assert.ErrorContains(t, err, "can't be deleted")
+
+	vi, err := rc.CreateVirtualInstance(ctx, "test", option.WithVirtualInstanceSize(option.SizeMedium))
+	require.NoError(t, err)
+	assert.Equal(t, "INITIALIZING", vi.GetState())
+	require.NoError(t, rc.Wait.UntilVirtualInstanceActive(ctx, vi.GetId()))
+
+	vi, err = rc.GetVirtualInstance(ctx, vi.GetId())
+	require.NoError(t, err)
+	assert.Equal(t, "MEDIUM", vi.GetCurrentSize())
+
+	_, err = rc.CreateCollection(ctx, "commons", "events")
+	require.NoError(t, err)
+	_, err = rc.MountCollections(ctx, vi.GetId(), []string{"commons.events"})
+	require.NoError(t, err)
+	require.NoError(t, rc.Wait.UntilMountActive(ctx, vi.GetId(), "commons", "events"))
+
+	_, err = rc.UnmountCollection(ctx, vi.GetId(), "commons.events")
+	require.NoError(t, err)
+	require.NoError(t, rc.Wait.UntilMountGone(ctx, vi.GetId(), "commons", "events"))
+
+	_, err = rc.DeleteVirtualInstance(ctx, vi.GetId())
+	require.NoError(t, err)
+	require.NoError(t, rc.Wait.UntilVirtualInstanceGone(ctx, vi.GetId()))
+}
+
+func TestServer_ValidateQuery(t *testing.T) {
+	ctx := context.TODO()
+	srv := NewServer()
+	defer srv.Close()
+	rc := NewClient(t, srv)
+
+	valid := []string{
+		"SELECT * FROM commons._events",
+		"SELECT * FROM _events",
+		"WITH recent AS (SELECT * FROM _events) SELECT * FROM recent",
+		"SELECT * FROM UNNEST(ARRAY [1, 2] AS n)",
+	}
+	for _, sql := range valid {
+		_, err := rc.ValidateQuery(ctx, sql)
+		assert.NoError(t, err, sql)
+	}
+
+	missing := map[string]string{
+		"SELECT * FROM commons.orders": "Collection 'commons.orders' not found",
+		"SELECT * FROM orders":         "Collection 'commons.orders' not found",
+		"SELECT * FROM _input":         "Collection 'commons._input' not found",
+	}
+	for sql, msg := range missing {
+		_, err := rc.ValidateQuery(ctx, sql)
+		assert.ErrorContains(t, err, msg, sql)
+	}
+}
@@ -0,0 +1,167 @@
+package rocksettest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+// sourceSuspended is the state of a suspended source.
+const sourceSuspended = "SUSPENDED"
+
+// newSource initializes a source which is added to a collection, and responds with an error if its integration
+// doesn't exist.
+func (s *Server) newSource(w http.ResponseWriter, src *openapi.Source) bool {
+	if src.IntegrationName != nil {
+		if _, found := s.integrations[src.GetIntegrationName()]; !found {
+			notFound(w, "Integration", src.GetIntegrationName())
+			return false
+		}
+	}
+
+	src.Id = openapi.PtrString(newID())
+	src.Status = &openapi.Status{State: openapi.PtrString("INITIALIZING")}
+	if src.Kafka != nil {
+		src.Kafka.Status = &openapi.StatusKafka{State: openapi.PtrString("NO_DOCS_YET")}
+	}
+
+	return true
+}
+
+// activeCollection returns the collection, or responds with an error if it doesn't exist.
+func (s *Server) activeCollection(w http.ResponseWriter, r *request) (*collection, bool) {
+	ws, name := r.params["workspace"], r.params["collection"]
+	c, found := s.collections[path(ws, name)]
+	if !found || c.deleted {
+		notFound(w, "Collection", path(ws, name))
+		return nil, false
+	}
+
+	return c, true
+}
+
+func (s *Server) createSource(w http.ResponseWriter, r *request) {
+	var src openapi.Source
+	if !r.decode(w, &src) {
+		return
+	}
+	c, ok := s.activeCollection(w, r)
+	if !ok || !s.newSource(w, &src) {
+		return
+	}
+	c.Sources = append(c.Sources, src)
+
+	writeData(w, http.StatusOK, src)
+}
+
+func (s *Server) listCollectionSources(w http.ResponseWriter, r *request) {
+	c, ok := s.activeCollection(w, r)
+	if !ok {
+		return
+	}
+	c.resumeDueSources()
+
+	writeData(w, http.StatusOK, c.Sources)
+}
+
+// source returns the source of the collection, or responds with an error if it doesn't exist.
+func (s *Server) source(w http.ResponseWriter, r *request) (*openapi.Source, bool) {
+	c, ok := s.activeCollection(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	c.resumeDueSources()
+
+	id := r.params["source"]
+	for i := range c.Sources {
+		if c.Sources[i].GetId() == id {
+			return &c.Sources[i], true
+		}
+	}
+
+	notFound(w, "Source", id)
+	return nil, false
+}
+
+func (s *Server) getSource(w http.ResponseWriter, r *request) {
+	if src, ok := s.source(w, r); ok {
+		writeData(w, http.StatusOK, src)
+	}
+}
+
+// suspendSource suspends the source until it resumes after the requested duration, which defaults to an hour.
+func (s *Server) suspendSource(w http.ResponseWriter, r *request) {
+	var req openapi.SuspendSourceRequest
+	if r.ContentLength != 0 && !r.decode(w, &req) {
+		return
+	}
+	resumeAfter := time.Hour
+	if req.ResumeAfterDuration != nil {
+		d, err := time.ParseDuration(req.GetResumeAfterDuration())
+		if err != nil || d <= 0 {
+			badRequest(w, "invalid resume_after_duration %q", req.GetResumeAfterDuration())
+			return
+		}
+		resumeAfter = d
+	}
+
+	src, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	src.Status = &openapi.Status{State: openapi.PtrString(sourceSuspended)}
+	src.SuspendedAt = openapi.PtrString(timestamp())
+	src.ResumeAt = openapi.PtrString(time.Now().Add(resumeAfter).UTC().Format(time.RFC3339))
+
+	writeData(w, http.StatusOK, src)
+}
+
+func (s *Server) resumeSource(w http.ResponseWriter, r *request) {
+	src, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	src.Status = &openapi.Status{State: openapi.PtrString("WATCHING")}
+	src.SuspendedAt = nil
+	src.ResumeAt = nil
+
+	writeData(w, http.StatusOK, src)
+}
+
+// resumeDueSources resumes the suspended sources whose resume_at has passed, like Rockset does.
+func (c *collection) resumeDueSources() {
+	for i := range c.Sources {
+		src := &c.Sources[i]
+		if src.Status.GetState() != sourceSuspended {
+			continue
+		}
+		resumeAt, err := time.Parse(time.RFC3339, src.GetResumeAt())
+		if err != nil || time.Now().Before(resumeAt) {
+			continue
+		}
+
+		src.Status = &openapi.Status{State: openapi.PtrString("WATCHING")}
+		src.SuspendedAt = nil
+		src.ResumeAt = nil
+	}
+}
+
+func (s *Server) deleteSource(w http.ResponseWriter, r *request) {
+	c, ok := s.activeCollection(w, r)
+	if !ok {
+		return
+	}
+
+	id := r.params["source"]
+	for i, src := range c.Sources {
+		if src.GetId() == id {
+			c.Sources = append(c.Sources[:i:i], c.Sources[i+1:]...)
+			writeData(w, http.StatusOK, src)
+			return
+		}
+	}
+
+	notFound(w, "Source", id)
+}
@@ -0,0 +1,142 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type view struct {
+	openapi.View
+	state   lifecycle
+	deleted bool
+}
+
+func (s *Server) createView(w http.ResponseWriter, r *request) {
+	ws := r.params["workspace"]
+	var req openapi.CreateViewRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid view name %s", req.Name)
+		return
+	}
+	if !s.activeWorkspace(w, ws) {
+		return
+	}
+	if _, found := s.views[path(ws, req.Name)]; found {
+		alreadyExists(w, "View", path(ws, req.Name))
+		return
+	}
+	if req.Query == "" {
+		badRequest(w, "the query of view %s is empty", path(ws, req.Name))
+		return
+	}
+
+	now := timestamp()
+	v := &view{
+		View: openapi.View{
+			Name:         openapi.PtrString(req.Name),
+			Workspace:    openapi.PtrString(ws),
+			Path:         openapi.PtrString(path(ws, req.Name)),
+			Description:  req.Description,
+			QuerySql:     openapi.PtrString(req.Query),
+			CreatorEmail: openapi.PtrString(r.user),
+			OwnerEmail:   openapi.PtrString(r.user),
+			CreatedAt:    openapi.PtrString(now),
+			ModifiedAt:   openapi.PtrString(now),
+		},
+		state: newLifecycle("SYNCING", "CREATED"),
+	}
+	s.views[path(ws, req.Name)] = v
+
+	writeData(w, http.StatusOK, v.data())
+}
+
+func (s *Server) getView(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["view"]
+	v, found := s.views[path(ws, name)]
+	if !found {
+		notFound(w, "View", path(ws, name))
+		return
+	}
+
+	if v.deleted {
+		delete(s.views, path(ws, name))
+	} else {
+		v.state.next()
+	}
+
+	writeData(w, http.StatusOK, v.data())
+}
+
+func (s *Server) listViews(w http.ResponseWriter, r *request) {
+	ws, inWorkspace := r.params["workspace"]
+	if inWorkspace && !s.activeWorkspace(w, ws) {
+		return
+	}
+
+	paths := make([]string, 0, len(s.views))
+	for p, v := range s.views {
+		if !inWorkspace || v.GetWorkspace() == ws {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	list := make([]openapi.View, 0, len(paths))
+	for _, p := range paths {
+		list = append(list, s.views[p].data())
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) updateView(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["view"]
+	v, found := s.views[path(ws, name)]
+	if !found || v.deleted {
+		notFound(w, "View", path(ws, name))
+		return
+	}
+
+	var req openapi.UpdateViewRequest
+	if !r.decode(w, &req) {
+		return
+	}
+	if req.Query == "" {
+		badRequest(w, "the query of view %s is empty", path(ws, name))
+		return
+	}
+
+	v.QuerySql = openapi.PtrString(req.Query)
+	v.Description = req.Description
+	v.ModifiedAt = openapi.PtrString(timestamp())
+	v.state = newLifecycle("SYNCING", "CREATED")
+
+	writeData(w, http.StatusOK, v.data())
+}
+
+func (s *Server) deleteView(w http.ResponseWriter, r *request) {
+	ws, name := r.params["workspace"], r.params["view"]
+	v, found := s.views[path(ws, name)]
+	if !found || v.deleted {
+		notFound(w, "View", path(ws, name))
+		return
+	}
+
+	v.deleted = true
+	v.state = newLifecycle("DELETED")
+
+	writeData(w, http.StatusOK, v.data())
+}
+
+func (v *view) data() openapi.View {
+	data := v.View
+	data.State = openapi.PtrString(v.state.current())
+
+	return data
+}
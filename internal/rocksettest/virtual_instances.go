@@ -0,0 +1,345 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type virtualInstance struct {
+	openapi.VirtualInstance
+	state   lifecycle
+	deleted bool
+}
+
+type mount struct {
+	openapi.CollectionMount
+	state   lifecycle
+	deleted bool
+}
+
+func mountID(vi, collectionPath string) string {
+	return vi + "/" + collectionPath
+}
+
+func (s *Server) createVirtualInstance(w http.ResponseWriter, r *request) {
+	var req openapi.CreateVirtualInstanceRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid virtual instance name %s", req.Name)
+		return
+	}
+	for _, vi := range s.virtualInstances {
+		if vi.Name == req.Name && !vi.deleted {
+			alreadyExists(w, "Virtual Instance", req.Name)
+			return
+		}
+	}
+
+	size := req.GetType()
+	if size == "" {
+		size = "SMALL"
+	}
+
+	id := newID()
+	vi := &virtualInstance{
+		VirtualInstance: openapi.VirtualInstance{
+			Id:                          openapi.PtrString(id),
+			Rrn:                         openapi.PtrString(rrn("vi", id)),
+			Name:                        req.Name,
+			Description:                 req.Description,
+			DefaultVi:                   openapi.PtrBool(false),
+			DesiredSize:                 openapi.PtrString(size),
+			AutoSuspendSeconds:          req.AutoSuspendSeconds,
+			EnableRemountOnResume:       openapi.PtrBool(req.GetEnableRemountOnResume()),
+			MountRefreshIntervalSeconds: req.MountRefreshIntervalSeconds,
+			MountType:                   req.MountType,
+			MonitoringEnabled:           openapi.PtrBool(false),
+			CreatedAt:                   openapi.PtrString(timestamp()),
+			CreatedBy:                   openapi.PtrString(r.user),
+		},
+		state: newLifecycle("INITIALIZING", "PROVISIONING_RESOURCES", "ACTIVE"),
+	}
+	s.virtualInstances[id] = vi
+
+	writeData(w, http.StatusOK, vi.data())
+}
+
+func (s *Server) getVirtualInstance(w http.ResponseWriter, r *request) {
+	id := r.params["virtualInstanceId"]
+	vi, found := s.virtualInstances[id]
+	if !found {
+		notFound(w, "Virtual Instance", id)
+		return
+	}
+
+	if vi.deleted {
+		delete(s.virtualInstances, id)
+	} else {
+		vi.state.next()
+	}
+
+	writeData(w, http.StatusOK, vi.data())
+}
+
+func (s *Server) listVirtualInstances(w http.ResponseWriter, _ *request) {
+	ids := make([]string, 0, len(s.virtualInstances))
+	for id := range s.virtualInstances {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := make([]openapi.VirtualInstance, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, s.virtualInstances[id].data())
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+// updateVirtualInstance updates the virtual instance, which has to provision resources when it is resized.
+func (s *Server) updateVirtualInstance(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	var req openapi.UpdateVirtualInstanceRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if req.Name != nil {
+		if !nameRe.MatchString(req.GetName()) {
+			badRequest(w, "invalid virtual instance name %s", req.GetName())
+			return
+		}
+		vi.Name = req.GetName()
+	}
+	if req.Description != nil {
+		vi.Description = req.Description
+	}
+	if req.AutoSuspendSeconds != nil {
+		vi.AutoSuspendSeconds = req.AutoSuspendSeconds
+	}
+	if req.AutoSuspendEnabled != nil && !req.GetAutoSuspendEnabled() {
+		vi.AutoSuspendSeconds = nil
+	}
+	if req.EnableRemountOnResume != nil {
+		vi.EnableRemountOnResume = req.EnableRemountOnResume
+	}
+	if req.MountRefreshIntervalSeconds != nil {
+		vi.MountRefreshIntervalSeconds = req.MountRefreshIntervalSeconds
+	}
+	if req.MountType != nil {
+		vi.MountType = req.MountType
+	}
+	if req.AutoScalingPolicy != nil {
+		vi.AutoScalingPolicy = req.AutoScalingPolicy
+	}
+	if req.NewSize != nil && req.GetNewSize() != vi.GetDesiredSize() {
+		vi.DesiredSize = req.NewSize
+		vi.state = newLifecycle("PROVISIONING_RESOURCES", "ACTIVE")
+	}
+
+	writeData(w, http.StatusOK, vi.data())
+}
+
+func (s *Server) deleteVirtualInstance(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	if vi.GetDefaultVi() {
+		badRequest(w, "the default virtual instance %s can't be deleted", vi.GetId())
+		return
+	}
+
+	vi.deleted = true
+	vi.state = newLifecycle("DELETED")
+	for id, m := range s.mounts {
+		if m.GetVirtualInstanceId() == vi.GetId() {
+			delete(s.mounts, id)
+		}
+	}
+
+	writeData(w, http.StatusOK, vi.data())
+}
+
+func (s *Server) suspendVirtualInstance(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	if vi.GetDefaultVi() {
+		badRequest(w, "the default virtual instance %s can't be suspended", vi.GetId())
+		return
+	}
+	if vi.state.current() != "ACTIVE" {
+		badRequest(w, "virtual instance %s is %s", vi.GetId(), vi.state.current())
+		return
+	}
+
+	vi.state = newLifecycle("SUSPENDING", "SUSPENDED")
+	writeData(w, http.StatusOK, vi.data())
+}
+
+func (s *Server) resumeVirtualInstance(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	if vi.state.current() != "SUSPENDED" {
+		badRequest(w, "virtual instance %s is %s", vi.GetId(), vi.state.current())
+		return
+	}
+
+	vi.state = newLifecycle("RESUMING", "ACTIVE")
+	vi.ResumedAt = openapi.PtrString(timestamp())
+	writeData(w, http.StatusOK, vi.data())
+}
+
+// virtualInstance returns the virtual instance of the request, and writes an error response if it doesn't exist.
+func (s *Server) virtualInstance(w http.ResponseWriter, r *request) (*virtualInstance, bool) {
+	id := r.params["virtualInstanceId"]
+	vi, found := s.virtualInstances[id]
+	if !found || vi.deleted {
+		notFound(w, "Virtual Instance", id)
+		return nil, false
+	}
+
+	return vi, true
+}
+
+func (vi *virtualInstance) data() openapi.VirtualInstance {
+	data := vi.VirtualInstance
+	data.State = openapi.PtrString(vi.state.current())
+	// the current size only catches up with the desired size once the resources are provisioned
+	if vi.state.current() == "ACTIVE" || data.CurrentSize == nil {
+		data.CurrentSize = data.DesiredSize
+		vi.CurrentSize = data.DesiredSize
+	}
+
+	return data
+}
+
+func (s *Server) mountCollections(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	var req openapi.CreateCollectionMountRequest
+	if !r.decode(w, &req) {
+		return
+	}
+	if !s.collectionsExist(w, req.CollectionPaths) {
+		return
+	}
+
+	mounts := make([]openapi.CollectionMount, 0, len(req.CollectionPaths))
+	for _, p := range req.CollectionPaths {
+		m, found := s.mounts[mountID(vi.GetId(), p)]
+		if !found || m.deleted {
+			id := newID()
+			m = &mount{
+				CollectionMount: openapi.CollectionMount{
+					Id:                 openapi.PtrString(id),
+					Rrn:                openapi.PtrString(rrn("mount", id)),
+					CollectionPath:     openapi.PtrString(p),
+					VirtualInstanceId:  vi.Id,
+					VirtualInstanceRrn: vi.Rrn,
+					CreatedAt:          openapi.PtrString(timestamp()),
+				},
+				state: newLifecycle("CREATING", "ACTIVE"),
+			}
+			s.mounts[mountID(vi.GetId(), p)] = m
+		}
+		mounts = append(mounts, m.data())
+	}
+
+	writeData(w, http.StatusOK, mounts)
+}
+
+// getMount returns the mount, and like the API it fails with a bad request if the collection exists
+// but isn't mounted.
+func (s *Server) getMount(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	p := r.params["collectionPath"]
+	m, found := s.mounts[mountID(vi.GetId(), p)]
+	if !found {
+		if c, exists := s.collections[p]; !exists || c.deleted {
+			notFound(w, "Collection", p)
+			return
+		}
+		badRequest(w, "Collection %s is not mounted on virtual instance %s", p, vi.GetId())
+		return
+	}
+
+	if m.deleted {
+		delete(s.mounts, mountID(vi.GetId(), p))
+	} else {
+		m.state.next()
+	}
+
+	writeData(w, http.StatusOK, m.data())
+}
+
+func (s *Server) listMounts(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	ids := make([]string, 0)
+	for id, m := range s.mounts {
+		if m.GetVirtualInstanceId() == vi.GetId() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	list := make([]openapi.CollectionMount, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, s.mounts[id].data())
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) unmountCollection(w http.ResponseWriter, r *request) {
+	vi, ok := s.virtualInstance(w, r)
+	if !ok {
+		return
+	}
+
+	p := r.params["collectionPath"]
+	m, found := s.mounts[mountID(vi.GetId(), p)]
+	if !found || m.deleted {
+		badRequest(w, "Collection %s is not mounted on virtual instance %s", p, vi.GetId())
+		return
+	}
+
+	m.deleted = true
+	m.state = newLifecycle("DELETING")
+
+	writeData(w, http.StatusOK, m.data())
+}
+
+func (m *mount) data() openapi.CollectionMount {
+	data := m.CollectionMount
+	data.State = openapi.PtrString(m.state.current())
+
+	return data
+}
@@ -0,0 +1,138 @@
+package rocksettest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/rockset/rockset-go-client/openapi"
+)
+
+type workspace struct {
+	openapi.Workspace
+	deleted bool
+}
+
+func (s *Server) createWorkspace(w http.ResponseWriter, r *request) {
+	var req openapi.CreateWorkspaceRequest
+	if !r.decode(w, &req) {
+		return
+	}
+
+	if !nameRe.MatchString(req.Name) {
+		badRequest(w, "invalid workspace name %s", req.Name)
+		return
+	}
+	if _, found := s.workspaces[req.Name]; found {
+		alreadyExists(w, "Workspace", req.Name)
+		return
+	}
+
+	ws := &workspace{Workspace: openapi.Workspace{
+		Name:        openapi.PtrString(req.Name),
+		Description: req.Description,
+		CreatedAt:   openapi.PtrString(timestamp()),
+		CreatedBy:   openapi.PtrString(r.user),
+	}}
+	s.workspaces[req.Name] = ws
+
+	writeData(w, http.StatusOK, s.workspaceData(ws))
+}
+
+func (s *Server) getWorkspace(w http.ResponseWriter, r *request) {
+	name := r.params["workspace"]
+	ws, found := s.workspaces[name]
+	if !found {
+		notFound(w, "Workspace", name)
+		return
+	}
+
+	// deleted workspaces are returned one last time, as the deletion is asynchronous
+	if ws.deleted {
+		delete(s.workspaces, name)
+	}
+
+	writeData(w, http.StatusOK, s.workspaceData(ws))
+}
+
+func (s *Server) listWorkspaces(w http.ResponseWriter, _ *request) {
+	names := make([]string, 0, len(s.workspaces))
+	for name := range s.workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]openapi.Workspace, 0, len(names))
+	for _, name := range names {
+		list = append(list, s.workspaceData(s.workspaces[name]))
+	}
+
+	writeData(w, http.StatusOK, list)
+}
+
+func (s *Server) deleteWorkspace(w http.ResponseWriter, r *request) {
+	name := r.params["workspace"]
+	ws, found := s.workspaces[name]
+	if !found || ws.deleted {
+		notFound(w, "Workspace", name)
+		return
+	}
+
+	if !s.workspaceEmpty(name) {
+		badRequest(w, "Workspace %s is not empty", name)
+		return
+	}
+
+	ws.deleted = true
+	writeData(w, http.StatusOK, s.workspaceData(ws))
+}
+
+// workspaceEmpty checks if the workspace doesn't contain any collections, aliases, views or query lambdas,
+// as only empty workspaces can be deleted.
+func (s *Server) workspaceEmpty(name string) bool {
+	for _, c := range s.collections {
+		if c.GetWorkspace() == name {
+			return false
+		}
+	}
+	for _, a := range s.aliases {
+		if a.GetWorkspace() == name {
+			return false
+		}
+	}
+	for _, v := range s.views {
+		if v.GetWorkspace() == name {
+			return false
+		}
+	}
+	for _, ql := range s.queryLambdas {
+		if ql.workspace == name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// activeWorkspace checks that the workspace exists, and writes an error response if it doesn't.
+func (s *Server) activeWorkspace(w http.ResponseWriter, name string) bool {
+	if ws, found := s.workspaces[name]; !found || ws.deleted {
+		notFound(w, "Workspace", name)
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) workspaceData(ws *workspace) openapi.Workspace {
+	var count int64
+	for _, c := range s.collections {
+		if c.GetWorkspace() == ws.GetName() {
+			count++
+		}
+	}
+
+	data := ws.Workspace
+	data.CollectionCount = &count
+
+	return data
+}
@@ -0,0 +1,53 @@
+package sqlscan
+
+import (
+	"strings"
+)
+
+// Normalize returns sql in a canonical form, where comments, whitespace and trailing semicolons are removed and
+// keywords, function names and the type names of casts are in a single case, so SQL which only differs in formatting
+// normalizes the same. Identifiers are case-sensitive in Rockset, so their case is kept.
+func Normalize(sql string) string {
+	tokens := StripComments(Tokenize(sql))
+	for len(tokens) > 0 && tokens[len(tokens)-1].IsSymbol(";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	// casts is the paren depths which were opened by CAST or TRY_CAST, where AS is followed by a type name
+	casts := make(map[int]bool)
+	depth := 0
+
+	parts := make([]string, len(tokens))
+	for i, t := range tokens {
+		switch {
+		case t.IsSymbol("("):
+			depth++
+		case t.IsSymbol(")"):
+			delete(casts, depth)
+			depth--
+		}
+
+		switch {
+		case t.Kind == Keyword:
+			parts[i] = strings.ToUpper(t.Text)
+			if (t.IsKeyword("CAST") || t.IsKeyword("TRY_CAST")) && i+1 < len(tokens) && tokens[i+1].IsSymbol("(") {
+				casts[depth+1] = true
+			}
+		case t.Kind == Identifier && i+1 < len(tokens) && tokens[i+1].IsSymbol("("):
+			// function names are case-insensitive
+			parts[i] = strings.ToLower(t.Text)
+		case t.Kind == Identifier && i > 0 && tokens[i-1].IsKeyword("AS") && casts[depth]:
+			// type names are case-insensitive
+			parts[i] = strings.ToUpper(t.Text)
+		default:
+			parts[i] = t.Text
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Equivalent checks if a and b are the same SQL, apart from formatting.
+func Equivalent(a, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
@@ -0,0 +1,104 @@
+package sqlscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "SELECT count ( * ) FROM commons . _events WHERE kind = :kind",
+		Normalize("select COUNT(*)\nfrom commons._events\n-- only one kind\nwhere kind = :kind;\n"))
+}
+
+func TestEquivalent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{
+			name: "whitespace",
+			a:    "SELECT * FROM commons._events",
+			b:    "\n  SELECT *\n\tFROM   commons._events\n",
+			same: true,
+		},
+		{
+			name: "keyword case",
+			a:    "SELECT * FROM commons._events WHERE a IS NOT NULL",
+			b:    "select * from commons._events where a is not null",
+			same: true,
+		},
+		{
+			name: "function case",
+			a:    "SELECT COUNT(*), CURRENT_TIMESTAMP() FROM commons._events",
+			b:    "SELECT count(*), current_timestamp() FROM commons._events",
+			same: true,
+		},
+		{
+			name: "comments",
+			a:    "SELECT * -- everything\nFROM /* the */ commons._events",
+			b:    "SELECT * FROM commons._events",
+			same: true,
+		},
+		{
+			name: "trailing semicolons",
+			a:    "SELECT * FROM commons._events;;",
+			b:    "SELECT * FROM commons._events",
+			same: true,
+		},
+		{
+			name: "ingest transformation",
+			a:    "SELECT *, CAST(_input.ts AS timestamp) AS _event_time\nFROM _input",
+			b:    "select *, cast(_input.ts as timestamp) as _event_time from _input",
+			same: true,
+		},
+		{
+			name: "type case",
+			a:    "SELECT CAST(x AS string), TRY_CAST(y AS Int) AS y FROM _input",
+			b:    "SELECT CAST(x AS STRING), try_cast(y AS int) AS y FROM _input",
+			same: true,
+		},
+		{
+			name: "alias case",
+			a:    "SELECT CAST(x AS string) AS total FROM _input",
+			b:    "SELECT CAST(x AS string) AS Total FROM _input",
+		},
+		{
+			name: "parameters",
+			a:    "SELECT * FROM commons._events LIMIT :limit",
+			b:    "SELECT * FROM commons._events LIMIT :Limit",
+		},
+		{
+			name: "identifier case",
+			a:    "SELECT Name FROM commons._events",
+			b:    "SELECT name FROM commons._events",
+		},
+		{
+			name: "string literal",
+			a:    "SELECT * FROM commons._events WHERE kind = 'A'",
+			b:    "SELECT * FROM commons._events WHERE kind = 'a'",
+		},
+		{
+			name: "whitespace in string literal",
+			a:    "SELECT 'a  b'",
+			b:    "SELECT 'a b'",
+		},
+		{
+			name: "quoted identifier",
+			a:    `SELECT "Name" FROM commons._events`,
+			b:    `SELECT "name" FROM commons._events`,
+		},
+		{
+			name: "semicolon in string",
+			a:    "SELECT ';'",
+			b:    "SELECT ''",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			assert.Equal(t, tst.same, Equivalent(tst.a, tst.b))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package sqlscan
+
+// DefaultWorkspace is the workspace of collections, aliases and views which SQL references without a workspace.
+const DefaultWorkspace = "commons"
+
+// Reference is a collection, alias or view which SQL queries.
+type Reference struct {
+	Workspace string
+	Name      string
+}
+
+// String returns the reference as workspace.name.
+func (r Reference) String() string {
+	return r.Workspace + "." + r.Name
+}
+
+// clauseKeywords end the FROM clause they follow.
+var clauseKeywords = []string{"WHERE", "GROUP", "HAVING", "ORDER", "LIMIT", "OFFSET", "FETCH", "UNION", "EXCEPT",
+	"INTERSECT", "WINDOW", "ON", "USING", "HINT"}
+
+// References returns the collections, aliases and views which sql queries in FROM and JOIN clauses, in the order
+// they first appear. Names without a workspace are in DefaultWorkspace, except _input and the names of WITH
+// queries. Table functions such as UNNEST and subqueries aren't references, and neither is the FROM in the arguments
+// of functions such as EXTRACT(YEAR FROM ts).
+func References(sql string) []Reference {
+	tokens := StripComments(Tokenize(sql))
+	with := withNames(tokens)
+
+	var refs []Reference
+	seen := make(map[Reference]bool)
+
+	// inFrom is the paren depths which are in a FROM clause, where a comma separates relations
+	inFrom := make(map[int]bool)
+	// calls is the paren depths which were opened by a function call, whose arguments have no relations
+	calls := make(map[int]bool)
+	depth := 0
+
+	for i, t := range tokens {
+		relation := false
+		switch {
+		case t.IsSymbol("("):
+			depth++
+			if i > 0 && isName(tokens[i-1]) {
+				calls[depth] = true
+			}
+		case t.IsSymbol(")"):
+			delete(inFrom, depth)
+			delete(calls, depth)
+			depth--
+		case t.IsKeyword("FROM") && calls[depth]:
+		case t.IsKeyword("FROM"):
+			inFrom[depth] = true
+			relation = true
+		case t.IsKeyword("JOIN"):
+			relation = true
+		case t.IsSymbol(","):
+			relation = inFrom[depth]
+		case t.Kind == Keyword:
+			for _, kw := range clauseKeywords {
+				if t.IsKeyword(kw) {
+					delete(inFrom, depth)
+				}
+			}
+		}
+		if !relation {
+			continue
+		}
+
+		ref, ok := reference(tokens[i+1:])
+		if !ok {
+			continue
+		}
+		if ref.Workspace == "" {
+			if ref.Name == "_input" || with[ref.Name] {
+				continue
+			}
+			ref.Workspace = DefaultWorkspace
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs
+}
+
+// reference returns the name at the start of tokens, which has no workspace if it isn't qualified.
+func reference(tokens []Token) (Reference, bool) {
+	if len(tokens) == 0 || !isName(tokens[0]) {
+		return Reference{}, false
+	}
+
+	if len(tokens) >= 3 && tokens[1].IsSymbol(".") && isName(tokens[2]) {
+		return Reference{Workspace: tokens[0].Name(), Name: tokens[2].Name()}, true
+	}
+	// a name followed by a parenthesis is a table function
+	if len(tokens) >= 2 && tokens[1].IsSymbol("(") {
+		return Reference{}, false
+	}
+
+	return Reference{Name: tokens[0].Name()}, true
+}
+
+// withNames returns the names of the WITH queries, as in WITH name AS (...), name AS (...).
+func withNames(tokens []Token) map[string]bool {
+	names := make(map[string]bool)
+	for i := 1; i+2 < len(tokens); i++ {
+		if (tokens[i-1].IsKeyword("WITH") || tokens[i-1].IsSymbol(",")) && isName(tokens[i]) &&
+			tokens[i+1].IsKeyword("AS") && tokens[i+2].IsSymbol("(") {
+			names[tokens[i].Name()] = true
+		}
+	}
+
+	return names
+}
+
+func isName(t Token) bool {
+	return t.Kind == Identifier || t.Kind == QuotedIdentifier
+}
@@ -0,0 +1,106 @@
+package sqlscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		refs []string
+	}{
+		{
+			name: "qualified",
+			sql:  "SELECT * FROM commons._events e WHERE e.kind = 'click'",
+			refs: []string{"commons._events"},
+		},
+		{
+			name: "unqualified",
+			sql:  "SELECT * FROM _events",
+			refs: []string{"commons._events"},
+		},
+		{
+			name: "quoted",
+			sql:  `SELECT * FROM "my-ws"."my-collection"`,
+			refs: []string{"my-ws.my-collection"},
+		},
+		{
+			name: "joins",
+			sql: "SELECT * FROM sales.orders o JOIN sales.customers c ON o.customer = c._id " +
+				"LEFT OUTER JOIN sales.orders x ON x._id = o._id",
+			refs: []string{"sales.orders", "sales.customers"},
+		},
+		{
+			name: "comma join",
+			sql:  "SELECT * FROM sales.orders o, sales.customers c WHERE o.customer = c._id",
+			refs: []string{"sales.orders", "sales.customers"},
+		},
+		{
+			name: "commas outside from",
+			sql:  "SELECT a, b FROM sales.orders WHERE x IN (1, 2) ORDER BY a, b",
+			refs: []string{"sales.orders"},
+		},
+		{
+			name: "subquery",
+			sql:  "SELECT * FROM (SELECT * FROM sales.orders) o, sales.customers",
+			refs: []string{"sales.orders", "sales.customers"},
+		},
+		{
+			name: "with",
+			sql:  "WITH recent AS (SELECT * FROM sales.orders), big AS (SELECT * FROM recent) SELECT * FROM big",
+			refs: []string{"sales.orders"},
+		},
+		{
+			name: "unnest",
+			sql:  "SELECT i FROM sales.orders o CROSS JOIN UNNEST(o.items) AS i",
+			refs: []string{"sales.orders"},
+		},
+		{
+			name: "ingest transformation",
+			sql:  "SELECT *, CAST(_input.ts AS timestamp) AS _event_time FROM _input",
+		},
+		{
+			name: "extract",
+			sql:  "SELECT EXTRACT(YEAR FROM ts) AS year FROM commons.orders",
+			refs: []string{"commons.orders"},
+		},
+		{
+			name: "trim",
+			sql:  "SELECT TRIM(BOTH ' ' FROM name) FROM commons.customers",
+			refs: []string{"commons.customers"},
+		},
+		{
+			name: "substring",
+			sql:  "SELECT SUBSTRING(name FROM 1 FOR 3) FROM commons.customers c JOIN commons.orders o ON c._id = o.customer",
+			refs: []string{"commons.customers", "commons.orders"},
+		},
+		{
+			name: "subquery in a function call",
+			sql:  "SELECT COALESCE((SELECT MAX(total) FROM sales.orders), 0) FROM sales.customers",
+			refs: []string{"sales.orders", "sales.customers"},
+		},
+		{
+			name: "comments and strings",
+			sql:  "SELECT 'FROM x.y' -- FROM a.b\nFROM /* c.d */ sales.orders",
+			refs: []string{"sales.orders"},
+		},
+		{
+			name: "parameter",
+			sql:  "SELECT * FROM sales.orders WHERE _id = :id",
+			refs: []string{"sales.orders"},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			var refs []string
+			for _, ref := range References(tst.sql) {
+				refs = append(refs, ref.String())
+			}
+			assert.Equal(t, tst.refs, refs)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package sqlscan splits Rockset SQL into tokens without contacting Rockset, so SQL attributes can be compared,
+// checked and analysed offline. It doesn't parse the SQL, so it accepts SQL which Rockset would reject.
+package sqlscan
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kind is the kind of a Token.
+type Kind int
+
+const (
+	// Keyword is a reserved word, which is case-insensitive, e.g. SELECT.
+	Keyword Kind = iota
+	// Identifier is an unquoted name, e.g. of a field, collection or function, which is case-sensitive.
+	Identifier
+	// QuotedIdentifier is a name in double quotes or backticks, e.g. "my-collection".
+	QuotedIdentifier
+	// String is a string literal in single quotes.
+	String
+	// Number is a numeric literal.
+	Number
+	// Parameter is a query lambda parameter, e.g. :limit.
+	Parameter
+	// Symbol is an operator or punctuation, e.g. <= or (.
+	Symbol
+	// Comment is a -- or /* */ comment.
+	Comment
+)
+
+// Token is a token of SQL, and where in the SQL it starts. Lines and columns start at 1.
+type Token struct {
+	Kind   Kind
+	Text   string
+	Line   int
+	Column int
+	// Unterminated is true for a string, quoted identifier or block comment which isn't closed.
+	Unterminated bool
+}
+
+// IsSymbol checks if the token is the symbol s.
+func (t Token) IsSymbol(s string) bool {
+	return t.Kind == Symbol && t.Text == s
+}
+
+// IsKeyword checks if the token is the keyword kw, which must be upper case.
+func (t Token) IsKeyword(kw string) bool {
+	return t.Kind == Keyword && strings.ToUpper(t.Text) == kw
+}
+
+// Name returns the name of an identifier or parameter, without the quotes of a quoted identifier or the colon of
+// a parameter.
+func (t Token) Name() string {
+	if t.Kind == Parameter {
+		return strings.TrimPrefix(t.Text, ":")
+	}
+	if t.Kind != QuotedIdentifier || len(t.Text) < 2 {
+		return t.Text
+	}
+
+	quote := t.Text[:1]
+	name := strings.TrimPrefix(t.Text, quote)
+	if !t.Unterminated {
+		name = strings.TrimSuffix(name, quote)
+	}
+
+	return strings.ReplaceAll(name, quote+quote, quote)
+}
+
+// symbols are the symbols which are longer than one character.
+var symbols = []string{"<=", ">=", "<>", "!=", "||", "=>", "->"}
+
+// Tokenize returns the tokens of sql, including comments.
+func Tokenize(sql string) []Token {
+	s := scanner{src: []rune(sql), line: 1, column: 1}
+
+	var tokens []Token
+	for {
+		s.skipSpace()
+		if s.done() {
+			return tokens
+		}
+		tokens = append(tokens, s.next())
+	}
+}
+
+// StripComments returns the tokens which aren't comments.
+func StripComments(tokens []Token) []Token {
+	var code []Token
+	for _, t := range tokens {
+		if t.Kind != Comment {
+			code = append(code, t)
+		}
+	}
+
+	return code
+}
+
+type scanner struct {
+	src          []rune
+	pos          int
+	line, column int
+}
+
+func (s *scanner) done() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *scanner) peek(offset int) rune {
+	if s.pos+offset >= len(s.src) {
+		return 0
+	}
+	return s.src[s.pos+offset]
+}
+
+func (s *scanner) advance() {
+	if s.src[s.pos] == '\n' {
+		s.line++
+		s.column = 1
+	} else {
+		s.column++
+	}
+	s.pos++
+}
+
+func (s *scanner) skipSpace() {
+	for !s.done() && unicode.IsSpace(s.peek(0)) {
+		s.advance()
+	}
+}
+
+func (s *scanner) next() Token {
+	t := Token{Line: s.line, Column: s.column}
+	start := s.pos
+	r := s.peek(0)
+
+	switch {
+	case r == '-' && s.peek(1) == '-':
+		t.Kind = Comment
+		for !s.done() && s.peek(0) != '\n' {
+			s.advance()
+		}
+	case r == '/' && s.peek(1) == '*':
+		t.Kind = Comment
+		s.advance()
+		s.advance()
+		t.Unterminated = true
+		for !s.done() {
+			if s.peek(0) == '*' && s.peek(1) == '/' {
+				s.advance()
+				s.advance()
+				t.Unterminated = false
+				break
+			}
+			s.advance()
+		}
+	case r == '\'':
+		t.Kind = String
+		t.Unterminated = s.quoted(r)
+	case r == '"' || r == '`':
+		t.Kind = QuotedIdentifier
+		t.Unterminated = s.quoted(r)
+	case r == ':' && isWordStart(s.peek(1)):
+		t.Kind = Parameter
+		s.advance()
+		s.word()
+	case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(s.peek(1))):
+		t.Kind = Number
+		s.number()
+	case isWordStart(r):
+		s.word()
+		t.Kind = Identifier
+		if keywords[strings.ToUpper(string(s.src[start:s.pos]))] {
+			t.Kind = Keyword
+		}
+	default:
+		t.Kind = Symbol
+		s.advance()
+		for _, sym := range symbols {
+			if string(r)+string(s.peek(0)) == sym {
+				s.advance()
+				break
+			}
+		}
+	}
+
+	t.Text = string(s.src[start:s.pos])
+
+	return t
+}
+
+// quoted scans text in quotes, where two quotes are an escaped quote, and returns true if it isn't terminated.
+func (s *scanner) quoted(quote rune) bool {
+	s.advance()
+	for !s.done() {
+		if s.peek(0) == quote {
+			s.advance()
+			if s.peek(0) != quote {
+				return false
+			}
+		}
+		s.advance()
+	}
+
+	return true
+}
+
+func (s *scanner) word() {
+	for !s.done() && (isWordStart(s.peek(0)) || unicode.IsDigit(s.peek(0))) {
+		s.advance()
+	}
+}
+
+func (s *scanner) number() {
+	for !s.done() && (unicode.IsDigit(s.peek(0)) || s.peek(0) == '.') {
+		s.advance()
+	}
+	if r := s.peek(0); r == 'e' || r == 'E' {
+		if unicode.IsDigit(s.peek(1)) || ((s.peek(1) == '+' || s.peek(1) == '-') && unicode.IsDigit(s.peek(2))) {
+			s.advance()
+			s.advance()
+			for !s.done() && unicode.IsDigit(s.peek(0)) {
+				s.advance()
+			}
+		}
+	}
+}
+
+func isWordStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// keywords are the reserved words of Rockset SQL, which are case-insensitive.
+var keywords = map[string]bool{}
+
+func init() {
+	for _, kw := range strings.Fields(`
+		ALL AND ANY AS ASC BETWEEN BY CASE CAST CROSS CUBE CURRENT DELETE DESC DISTINCT ELSE END ESCAPE EXCEPT
+		EXISTS EXPLAIN FALSE FETCH FIRST FOLLOWING FOR FROM FULL GROUP GROUPING HAVING HINT IF IN INNER INSERT
+		INTERSECT INTERVAL INTO IS JOIN LAST LATERAL LEFT LIKE LIMIT NATURAL NEXT NOT NULL NULLS OFFSET ON ONLY OR
+		ORDER ORDINALITY OUTER OVER PARTITION PRECEDING RANGE RIGHT ROLLUP ROW ROWS SELECT SETS THEN TIES TRUE
+		TRY_CAST UNBOUNDED UNION UNNEST USING VALUES WHEN WHERE WINDOW WITH`) {
+		keywords[kw] = true
+	}
+}
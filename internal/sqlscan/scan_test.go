@@ -0,0 +1,66 @@
+package sqlscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	sql := "SELECT e.\"user-id\", COUNT(*) -- per user\n" +
+		"FROM commons._events e WHERE e.ts >= :since AND e.kind <> 'it''s' /* a\ncomment */ LIMIT 1.5e3;"
+
+	type tok struct {
+		kind Kind
+		text string
+	}
+	var got []tok
+	for _, token := range Tokenize(sql) {
+		got = append(got, tok{token.Kind, token.Text})
+	}
+
+	assert.Equal(t, []tok{
+		{Keyword, "SELECT"}, {Identifier, "e"}, {Symbol, "."}, {QuotedIdentifier, `"user-id"`}, {Symbol, ","},
+		{Identifier, "COUNT"}, {Symbol, "("}, {Symbol, "*"}, {Symbol, ")"}, {Comment, "-- per user"},
+		{Keyword, "FROM"}, {Identifier, "commons"}, {Symbol, "."}, {Identifier, "_events"}, {Identifier, "e"},
+		{Keyword, "WHERE"}, {Identifier, "e"}, {Symbol, "."}, {Identifier, "ts"}, {Symbol, ">="},
+		{Parameter, ":since"}, {Keyword, "AND"}, {Identifier, "e"}, {Symbol, "."}, {Identifier, "kind"},
+		{Symbol, "<>"}, {String, "'it''s'"}, {Comment, "/* a\ncomment */"}, {Keyword, "LIMIT"},
+		{Number, "1.5e3"}, {Symbol, ";"},
+	}, got)
+}
+
+func TestTokenize_Position(t *testing.T) {
+	tokens := Tokenize("SELECT *\n  FROM\t_input")
+
+	assert.Equal(t, Token{Kind: Keyword, Text: "FROM", Line: 2, Column: 3}, tokens[2])
+	assert.Equal(t, Token{Kind: Identifier, Text: "_input", Line: 2, Column: 8}, tokens[3])
+}
+
+func TestTokenize_Unterminated(t *testing.T) {
+	for _, sql := range []string{"SELECT 'abc", `SELECT "abc`, "SELECT `abc", "SELECT 1 /* abc"} {
+		tokens := Tokenize(sql)
+		assert.True(t, tokens[len(tokens)-1].Unterminated, sql)
+	}
+
+	tokens := Tokenize("SELECT 'abc'")
+	assert.False(t, tokens[1].Unterminated)
+}
+
+func TestToken_Name(t *testing.T) {
+	assert.Equal(t, "events", Token{Kind: Identifier, Text: "events"}.Name())
+	assert.Equal(t, "my-collection", Token{Kind: QuotedIdentifier, Text: `"my-collection"`}.Name())
+	assert.Equal(t, "a`b", Token{Kind: QuotedIdentifier, Text: "`a``b`"}.Name())
+	assert.Equal(t, "abc", Token{Kind: QuotedIdentifier, Text: `"abc`, Unterminated: true}.Name())
+	assert.Equal(t, "limit", Token{Kind: Parameter, Text: ":limit"}.Name())
+}
+
+func TestStripComments(t *testing.T) {
+	tokens := StripComments(Tokenize("-- all\nSELECT /* every */ * FROM _input"))
+
+	var text []string
+	for _, token := range tokens {
+		text = append(text, token.Text)
+	}
+	assert.Equal(t, []string{"SELECT", "*", "FROM", "_input"}, text)
+}
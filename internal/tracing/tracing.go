@@ -0,0 +1,122 @@
+// Package tracing adds optional OpenTelemetry tracing to the provider, which is enabled and configured using the
+// standard OTEL_* environment variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName  = "github.com/rockset/terraform-provider-rockset"
+	serviceName = "terraform-provider-rockset"
+)
+
+// Attribute keys used on the spans.
+const (
+	ResourceTypeKey = attribute.Key("rockset.resource.type")
+	ResourceIDKey   = attribute.Key("rockset.resource.id")
+	OperationKey    = attribute.Key("rockset.operation")
+)
+
+// Enabled checks if tracing is configured using the OTEL_* environment variables.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "otlp":
+		return true
+	case "":
+	default:
+		// only the OTLP exporter is supported, as stdout is used by the plugin protocol
+		return false
+	}
+
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs an OTLP exporter as the global tracer provider when tracing is enabled. The exporter protocol is
+// selected using OTEL_EXPORTER_OTLP_PROTOCOL, and the exporter reads the remaining OTEL_EXPORTER_OTLP_* variables.
+// The returned function flushes and stops the exporter, and must be called before the provider exits.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !Enabled() {
+		return noop, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	protocol := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL")
+	if protocol == "" {
+		protocol = os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+	switch protocol {
+	case "", "http/protobuf":
+		exporter, err = otlptracehttp.New(ctx)
+	case "grpc":
+		exporter, err = otlptracegrpc.New(ctx)
+	default:
+		return noop, fmt.Errorf("unsupported OTLP protocol %s", protocol)
+	}
+	if err != nil {
+		return noop, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence over the defaults
+	res, err := resource.Merge(
+		resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		),
+		resource.Environment(),
+	)
+	if err != nil {
+		return noop, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer used by the provider, which is a no-op unless tracing is enabled.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// StartOperation starts the span of a CRUD operation on a resource or data source.
+func StartOperation(ctx context.Context, resourceType, operation, id string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, resourceType+"."+operation, trace.WithAttributes(
+		ResourceTypeKey.String(resourceType),
+		OperationKey.String(operation),
+		ResourceIDKey.String(id),
+	))
+}
+
+// EndOperation ends the span of a CRUD operation, and records the ID of the resource and the error if it failed.
+func EndOperation(span trace.Span, id string, err error) {
+	span.SetAttributes(ResourceIDKey.String(id))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
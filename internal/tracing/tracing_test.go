@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rockset/rockset-go-client/wait"
+	"github.com/rockset/rockset-go-client/wait/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func testExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	return exporter
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, a := range span.Attributes {
+		m[a.Key] = a.Value
+	}
+
+	return m
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{"not configured", nil, false},
+		{"endpoint", map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318"}, true},
+		{"traces endpoint", map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318"}, true},
+		{"otlp exporter", map[string]string{"OTEL_TRACES_EXPORTER": "otlp"}, true},
+		{"no exporter", map[string]string{
+			"OTEL_TRACES_EXPORTER":        "none",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+		}, false},
+		{"disabled", map[string]string{
+			"OTEL_SDK_DISABLED":           "true",
+			"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+		}, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range []string{"OTEL_SDK_DISABLED", "OTEL_TRACES_EXPORTER", "OTEL_EXPORTER_OTLP_ENDPOINT",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"} {
+				t.Setenv(k, tc.env[k])
+			}
+
+			assert.Equal(t, tc.expected, Enabled())
+		})
+	}
+}
+
+func TestOperationSpans(t *testing.T) {
+	exporter := testExporter(t)
+	ctx := context.TODO()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	ctx, span := StartOperation(ctx, "rockset_workspace", "create", "")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/orgs/self/ws/test", nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	rg := &fake.FakeResourceGetter{}
+	rg.RetryWithCheckReturns(errors.New("timeout"))
+	err = wait.New(WaitResourceGetter(rg)).UntilWorkspaceAvailable(ctx, "test")
+	assert.Error(t, err)
+
+	EndOperation(span, "test", nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	httpSpan, waitSpan, opSpan := spans[0], spans[1], spans[2]
+
+	assert.Equal(t, "rockset_workspace.create", opSpan.Name)
+	assert.Equal(t, "rockset_workspace", attributes(opSpan)[ResourceTypeKey].AsString())
+	assert.Equal(t, "test", attributes(opSpan)[ResourceIDKey].AsString())
+	assert.Equal(t, codes.Unset, opSpan.Status.Code)
+
+	assert.Equal(t, "GET /v1/orgs/self/ws/test", httpSpan.Name)
+	assert.Equal(t, opSpan.SpanContext.SpanID(), httpSpan.Parent.SpanID())
+	assert.Equal(t, int64(http.StatusNotFound), attributes(httpSpan)["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Error, httpSpan.Status.Code)
+
+	assert.Equal(t, "wait.UntilWorkspaceAvailable", waitSpan.Name)
+	assert.Equal(t, opSpan.SpanContext.SpanID(), waitSpan.Parent.SpanID())
+	assert.Equal(t, codes.Error, waitSpan.Status.Code)
+}
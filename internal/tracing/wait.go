@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"runtime"
+	"strings"
+
+	"github.com/rockset/rockset-go-client/retry"
+	"github.com/rockset/rockset-go-client/wait"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// waitResourceGetter creates a span for every wait loop of a wait.Waiter.
+type waitResourceGetter struct {
+	wait.ResourceGetter
+}
+
+// WaitResourceGetter wraps rg so every rc.Wait.Until*() poll loop gets its own span, named after the method.
+//
+//	rc.Wait = wait.New(tracing.WaitResourceGetter(rc))
+func WaitResourceGetter(rg wait.ResourceGetter) wait.ResourceGetter {
+	return waitResourceGetter{rg}
+}
+
+// RetryWithCheck implements retry.Retrier, and is only called by the wait.Waiter poll loops.
+func (w waitResourceGetter) RetryWithCheck(ctx context.Context, checkFn retry.CheckFn) error {
+	ctx, span := Tracer().Start(ctx, "wait."+waitMethod())
+	defer span.End()
+
+	err := w.ResourceGetter.RetryWithCheck(ctx, checkFn)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+// waitMethod returns the name of the wait.Waiter method which called RetryWithCheck, e.g. UntilCollectionReady.
+func waitMethod() string {
+	pc := make([]uintptr, 1)
+	// skip runtime.Callers, waitMethod and RetryWithCheck
+	if runtime.Callers(3, pc) == 0 {
+		return "Until"
+	}
+
+	frame, _ := runtime.CallersFrames(pc).Next()
+	name := frame.Function
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
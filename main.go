@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
 
 	"github.com/rockset/terraform-provider-rockset/internal/provider"
+	"github.com/rockset/terraform-provider-rockset/internal/tracing"
 	"github.com/rockset/terraform-provider-rockset/rockset"
 )
 
@@ -22,18 +23,34 @@ var version = "dev"
 const FullyQualifiedProviderName = "registry.terraform.io/rockset/rockset"
 
 func main() {
-	ctx := context.Background()
-
 	var debug bool
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
+	if err := run(context.Background(), debug); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the provider until Terraform stops it. Errors are returned rather than exiting, so the traces are
+// flushed also when the provider fails to start.
+func run(ctx context.Context, debug bool) error {
+	shutdown, err := tracing.Setup(ctx, version)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			log.Printf("failed to flush traces: %v", err)
+		}
+	}()
+
 	// we're in process of migrating from the SDKv2 to the plugin framework, so we need to mux the two
 
 	// create an instance of the old SDKv2 provider
-	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx, rockset.Provider().GRPCProvider)
+	upgradedSdkServer, err := tf5to6server.UpgradeServer(ctx, rockset.ProviderServer)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	providers := []func() tfprotov6.ProviderServer{
@@ -47,7 +64,7 @@ func main() {
 
 	muxServer, err := tf6muxserver.NewMuxServer(ctx, providers...)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	var serveOpts []tf6server.ServeOpt
@@ -55,7 +72,5 @@ func main() {
 		serveOpts = append(serveOpts, tf6server.WithManagedDebug())
 	}
 
-	if err = tf6server.Serve(FullyQualifiedProviderName, muxServer.ProviderServer, serveOpts...); err != nil {
-		log.Fatal(err)
-	}
+	return tf6server.Serve(FullyQualifiedProviderName, muxServer.ProviderServer, serveOpts...)
 }
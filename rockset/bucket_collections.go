@@ -70,6 +70,7 @@ func flattenBucketSourceParams(ctx context.Context, sourceType string, sources *
 		}
 
 		m["integration_name"] = source.IntegrationName
+		flattenSourceSuspension(m, source)
 		switch sourceType {
 		case "gcs":
 			if source.Gcs == nil {
@@ -154,13 +155,13 @@ func makeBucketSourceParams(sourceType string, in interface{}) ([]openapi.Source
 func flattenCsvParams(params *openapi.CsvParams) []interface{} {
 	m := make(map[string]interface{})
 
-	m["first_line_as_column_names"] = *params.FirstLineAsColumnNames
-	m["separator"] = *params.Separator
-	m["encoding"] = *params.Encoding
-	m["escape_char"] = *params.EscapeChar
-	m["quote_char"] = *params.QuoteChar
-	m["column_names"] = params.ColumnNames
-	m["column_types"] = params.ColumnTypes
+	m["first_line_as_column_names"] = params.GetFirstLineAsColumnNames()
+	m["separator"] = params.GetSeparator()
+	m["encoding"] = params.GetEncoding()
+	m["escape_char"] = params.GetEscapeChar()
+	m["quote_char"] = params.GetQuoteChar()
+	m["column_names"] = params.GetColumnNames()
+	m["column_types"] = params.GetColumnTypes()
 
 	return []interface{}{m}
 }
@@ -196,11 +197,11 @@ func makeCsvParams(in interface{}) *openapi.CsvParams {
 
 func flattenXmlParams(params *openapi.XmlParams) []interface{} {
 	m := make(map[string]interface{})
-	m["root_tag"] = *params.RootTag
-	m["encoding"] = *params.Encoding
-	m["doc_tag"] = *params.DocTag
-	m["value_tag"] = *params.ValueTag
-	m["attribute_prefix"] = *params.AttributePrefix
+	m["root_tag"] = params.GetRootTag()
+	m["encoding"] = params.GetEncoding()
+	m["doc_tag"] = params.GetDocTag()
+	m["value_tag"] = params.GetValueTag()
+	m["attribute_prefix"] = params.GetAttributePrefix()
 
 	return []interface{}{m}
 }
@@ -0,0 +1,242 @@
+package rockset
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/dnaeon/go-vcr.v3/cassette"
+	"gopkg.in/dnaeon/go-vcr.v3/recorder"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// When VCR_MODE is record, the acceptance tests record their API calls to a cassette per test, and when it is
+// replay, they replay the API calls from the cassettes without contacting Rockset.
+const (
+	vcrModeEnv    = "VCR_MODE"
+	vcrRecord     = "record"
+	vcrReplay     = "replay"
+	vcrRedacted   = "REDACTED"
+	vcrAPIServer  = "https://api.rockset.invalid"
+	cassettesPath = "../testdata/cassettes"
+)
+
+var vcrMode = strings.ToLower(os.Getenv(vcrModeEnv))
+
+// vcr is the cassette of the running acceptance test, which is nil unless VCR_MODE is set.
+var vcr *testCassette
+
+// secretFieldRe matches the JSON fields of requests and responses which contain credentials.
+var secretFieldRe = regexp.MustCompile(
+	`("(?:` + strings.Join(client.SecretFields, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// cassetteTransport sends the requests through the recorder of the running test.
+type cassetteTransport struct {
+	mu   sync.Mutex
+	rec  *recorder.Recorder
+	real http.RoundTripper
+}
+
+func (c *cassetteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	rec := c.rec
+	c.mu.Unlock()
+
+	if rec == nil {
+		if vcrMode == vcrReplay {
+			return nil, fmt.Errorf("%s %s was called outside an acceptance test with a cassette", r.Method, r.URL)
+		}
+		return c.real.RoundTrip(r)
+	}
+
+	return rec.RoundTrip(r)
+}
+
+func (c *cassetteTransport) use(rec *recorder.Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rec = rec
+}
+
+var cassettes *cassetteTransport
+
+// setupCassettes routes the API calls of the acceptance tests through the cassettes. When replaying, the API key and
+// server aren't needed, so they are set to placeholders unless they are set.
+func setupCassettes() {
+	switch vcrMode {
+	case "":
+		return
+	case vcrRecord, vcrReplay:
+	default:
+		panic(fmt.Sprintf("%s must be %s or %s, not %s", vcrModeEnv, vcrRecord, vcrReplay, vcrMode))
+	}
+
+	base := client.BaseTransport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	cassettes = &cassetteTransport{real: base}
+	client.BaseTransport = cassettes
+
+	if vcrMode == vcrReplay {
+		setDefaultEnv("ROCKSET_APIKEY", vcrRedacted)
+		setDefaultEnv("ROCKSET_APISERVER", vcrAPIServer)
+	}
+}
+
+func setDefaultEnv(key, value string) {
+	if _, found := os.LookupEnv(key); !found {
+		os.Setenv(key, value)
+	}
+}
+
+// testCassette tracks what has to be normalized in the cassette of a test, so it can be replayed in another run.
+type testCassette struct {
+	// names maps the random names generated while recording to their normalized names
+	names map[string]string
+	// counts is the number of names generated for each prefix
+	counts map[string]int
+	// secrets are the values which are redacted
+	secrets []string
+}
+
+// useCassette records the API calls of the test to its cassette, or replays them, depending on VCR_MODE.
+// It must be called before the test generates any random names. In replay mode tests without a cassette fail, so
+// replaying doesn't pass without testing anything.
+func useCassette(t *testing.T) {
+	if cassettes == nil {
+		return
+	}
+
+	mode := recorder.ModeReplayOnly
+	if vcrMode == vcrRecord {
+		mode = recorder.ModeRecordOnly
+	}
+
+	rec, err := recorder.NewWithOptions(&recorder.Options{
+		CassetteName:       filepath.Join(cassettesPath, t.Name()),
+		Mode:               mode,
+		RealTransport:      cassettes.real,
+		SkipRequestLatency: true,
+	})
+	if err != nil {
+		if mode == recorder.ModeReplayOnly {
+			t.Fatalf("no cassette to replay, record it with %s=%s: %v", vcrModeEnv, vcrRecord, err)
+		}
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+
+	vcr = &testCassette{
+		names:  make(map[string]string),
+		counts: make(map[string]int),
+	}
+	vcr.secret(os.Getenv("ROCKSET_APIKEY"))
+	rec.SetMatcher(matchInteraction)
+	rec.AddHook(vcr.sanitize, recorder.BeforeSaveHook)
+	cassettes.use(rec)
+
+	t.Cleanup(func() {
+		cassettes.use(nil)
+		vcr = nil
+		if err := rec.Stop(); err != nil {
+			t.Errorf("failed to save cassette: %v", err)
+		}
+	})
+}
+
+// matchInteraction matches requests on the method and the path and query of the URL, as the API server differs
+// between the recording and the replay, and the request bodies contain redacted values.
+func matchInteraction(r *http.Request, i cassette.Request) bool {
+	u, err := url.Parse(i.URL)
+	if err != nil {
+		return false
+	}
+
+	return r.Method == i.Method && r.URL.Path == u.Path && r.URL.RawQuery == u.RawQuery
+}
+
+// name returns the normalized name for the next name with the prefix, and when recording remembers that the
+// random name should be replaced by it.
+func (c *testCassette) name(prefix, random string) string {
+	c.counts[prefix]++
+	name := fmt.Sprintf("tf_vcr_%s_%d", prefix, c.counts[prefix])
+	if random != "" {
+		c.names[random] = name
+		c.names[strings.ToLower(random)] = strings.ToLower(name)
+	}
+
+	return name
+}
+
+// secret redacts the value from the cassette.
+func (c *testCassette) secret(value string) {
+	if value != "" {
+		c.secrets = append(c.secrets, value)
+	}
+}
+
+// sanitize removes the credentials from the interaction, and normalizes the random names.
+func (c *testCassette) sanitize(i *cassette.Interaction) error {
+	i.Request.Headers.Del("Authorization")
+
+	replacements := make([]string, 0, 2*(len(c.secrets)+len(c.names)))
+	for _, s := range c.secrets {
+		replacements = append(replacements, s, vcrRedacted)
+	}
+	// replace longer names first, in case one name is a prefix of another
+	randoms := make([]string, 0, len(c.names))
+	for random := range c.names {
+		randoms = append(randoms, random)
+	}
+	sort.Slice(randoms, func(a, b int) bool { return len(randoms[a]) > len(randoms[b]) })
+	for _, random := range randoms {
+		replacements = append(replacements, random, c.names[random])
+	}
+	r := strings.NewReplacer(replacements...)
+
+	i.Request.URL = r.Replace(i.Request.URL)
+	i.Request.RequestURI = r.Replace(i.Request.RequestURI)
+	i.Request.Body = secretFieldRe.ReplaceAllString(r.Replace(i.Request.Body), `$1"`+vcrRedacted+`"`)
+	i.Response.Body = secretFieldRe.ReplaceAllString(r.Replace(i.Response.Body), `$1"`+vcrRedacted+`"`)
+	i.Request.ContentLength = int64(len(i.Request.Body))
+	i.Response.ContentLength = int64(len(i.Response.Body))
+	i.Response.Headers.Del("Content-Length")
+
+	return nil
+}
+
+func TestCassetteSanitize(t *testing.T) {
+	c := &testCassette{names: make(map[string]string), counts: make(map[string]int)}
+	c.secret("s3cr3t")
+	assert.Equal(t, "tf_vcr_ws_1", c.name("ws", "tf_dev_ws_AbCdEf"))
+	assert.Equal(t, "tf_vcr_ws_2", c.name("ws", "tf_dev_ws_AbCdEf_x"))
+
+	i := &cassette.Interaction{
+		Request: cassette.Request{
+			Headers: http.Header{"Authorization": {"ApiKey s3cr3t"}},
+			URL:     "https://api.usw2a1.rockset.com/v1/orgs/self/ws/tf_dev_ws_abcdef",
+			Body:    `{"name":"tf_dev_ws_AbCdEf_x","uri":"mongodb://s3cr3t@host"}`,
+		},
+		Response: cassette.Response{
+			Headers: http.Header{"Content-Length": {"42"}},
+			Body:    `{"data":{"name":"apikey","key":"very-secret","webhook_auth_header":"Bearer token"}}`,
+		},
+	}
+	require.NoError(t, c.sanitize(i))
+
+	assert.Empty(t, i.Request.Headers.Get("Authorization"))
+	assert.Equal(t, "https://api.usw2a1.rockset.com/v1/orgs/self/ws/tf_vcr_ws_1", i.Request.URL)
+	assert.Equal(t, `{"name":"tf_vcr_ws_2","uri":"mongodb://REDACTED@host"}`, i.Request.Body)
+	assert.Equal(t, `{"data":{"name":"apikey","key":"REDACTED","webhook_auth_header":"REDACTED"}}`, i.Response.Body)
+	assert.Empty(t, i.Response.Headers.Get("Content-Length"))
+}
@@ -0,0 +1,299 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/openapi"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// readinessProgressInterval is how often the progress of a collection which isn't ready yet is logged.
+const readinessProgressInterval = 30 * time.Second
+
+func waitForSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Conditions which all must be met before the collection is considered created, " +
+			"so resources which depend on the collection can use it. Only used when the collection is created.",
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"initial_ingest": {
+					Description: "Wait until the initial bulk ingest of every source has finished: the scan of " +
+						"DynamoDB and MongoDB sources has ended, and all detected bytes of S3 and GCS sources " +
+						"have been processed. Streaming sources have no initial bulk ingest.",
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"source_state": {
+					Description:  "Wait until every source is in this state, e.g. `WATCHING`.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				"kafka_offset_lag_below": {
+					Description: "Wait until the offset lag summed over all partitions of the Kafka sources " +
+						"is below this value. Use 1 to wait until the collection has caught up.",
+					Type:         schema.TypeInt,
+					Optional:     true,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"sql": {
+					Description: "Wait until this query returns a single row with a single column which is true, " +
+						"e.g. `SELECT COUNT(*) >= 1000 FROM commons.orders`.",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+		},
+	}
+}
+
+// validateWaitFor fails the plan when a collection which can't have Kafka sources waits for their offset lag,
+// rather than when the collection is created. Only Kafka collections can, and they need at least one source.
+func validateWaitFor(kafka bool) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if d.Id() != "" {
+			return nil
+		}
+		if _, found := d.GetOk("wait_for.0.kafka_offset_lag_below"); !found {
+			return nil
+		}
+
+		if !kafka || (d.NewValueKnown("source") && d.Get("source").(*schema.Set).Len() == 0) {
+			return fmt.Errorf("wait_for.0.kafka_offset_lag_below requires a Kafka source")
+		}
+
+		return nil
+	}
+}
+
+// readinessCondition checks if a collection is ready, and when it isn't, describes how far along it is.
+type readinessCondition func(ctx context.Context, rc *rockset.RockClient, c openapi.Collection) (bool, string, error)
+
+// readinessConditions returns the conditions of the wait_for block.
+func readinessConditions(d *schema.ResourceData) []readinessCondition {
+	blocks := d.Get("wait_for").([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	cfg := blocks[0].(map[string]interface{})
+
+	var conditions []readinessCondition
+	if cfg["initial_ingest"].(bool) {
+		conditions = append(conditions, initialIngestDone)
+	}
+	if state := cfg["source_state"].(string); state != "" {
+		conditions = append(conditions, sourcesInState(state))
+	}
+	if lag := cfg["kafka_offset_lag_below"].(int); lag > 0 {
+		conditions = append(conditions, kafkaOffsetLagBelow(int64(lag)))
+	}
+	if sql := cfg["sql"].(string); sql != "" {
+		conditions = append(conditions, queryIsTrue(sql))
+	}
+
+	return conditions
+}
+
+// waitForReadiness polls the collection until all conditions are met, and logs the progress periodically.
+func waitForReadiness(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData, workspace, name string,
+	conditions []readinessCondition) error {
+	var progress string
+	var logged time.Time
+
+	check := func(ctx context.Context) (bool, error) {
+		c, err := rc.GetCollection(ctx, workspace, name)
+		if err != nil {
+			return false, err
+		}
+
+		var pending []string
+		for _, condition := range conditions {
+			ready, status, err := condition(ctx, rc, c)
+			if err != nil {
+				return false, err
+			}
+			if !ready {
+				pending = append(pending, status)
+			}
+		}
+
+		progress = strings.Join(pending, "; ")
+		if len(pending) > 0 && time.Since(logged) >= readinessProgressInterval {
+			logged = time.Now()
+			tflog.Info(ctx, "waiting for collection to be ready", map[string]interface{}{
+				"workspace": workspace,
+				"name":      name,
+				"progress":  progress,
+			})
+		}
+
+		return len(pending) > 0, nil
+	}
+
+	return waitWithTimeout(ctx, d, schema.TimeoutCreate, "collection "+toID(workspace, name)+" to meet wait_for",
+		func(ctx context.Context) error {
+			return rc.RetryWithCheck(ctx, func() (bool, error) { return check(ctx) })
+		},
+		func(context.Context) (string, error) {
+			return progress, nil
+		})
+}
+
+// initialIngestDone is met when the bulk ingest of every source has finished.
+func initialIngestDone(_ context.Context, _ *rockset.RockClient, c openapi.Collection) (bool, string, error) {
+	var pending []string
+	for _, src := range c.Sources {
+		switch {
+		case src.Dynamodb != nil:
+			status := src.Dynamodb.GetStatus()
+			if _, ok := status.GetScanEndTimeOk(); !ok {
+				pending = append(pending, fmt.Sprintf("DynamoDB table %s scanned %d of %d records",
+					src.Dynamodb.GetTableName(), status.GetScanRecordsProcessed(), status.GetScanTotalRecords()))
+			}
+		case src.Mongodb != nil:
+			status := src.Mongodb.GetStatus()
+			if _, ok := status.GetScanEndTimeOk(); !ok {
+				pending = append(pending, fmt.Sprintf("MongoDB collection %s scanned %d of %d records",
+					src.Mongodb.GetCollectionName(), status.GetScanRecordsProcessed(), status.GetScanTotalRecords()))
+			}
+		case src.S3 != nil:
+			downloaded := src.S3.GetObjectBytesDownloaded()
+			if total, done := bytesProcessed(downloaded, src.S3.ObjectBytesTotal, src.Status); !done {
+				pending = append(pending, fmt.Sprintf("S3 bucket %s processed %d of %d bytes",
+					src.S3.GetBucket(), downloaded, total))
+			}
+		case src.Gcs != nil:
+			downloaded := src.Gcs.GetObjectBytesDownloaded()
+			if total, done := bytesProcessed(downloaded, src.Gcs.ObjectBytesTotal, src.Status); !done {
+				pending = append(pending, fmt.Sprintf("GCS bucket %s processed %d of %d bytes",
+					src.Gcs.GetBucket(), downloaded, total))
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		return false, "initial ingest: " + strings.Join(pending, ", "), nil
+	}
+
+	return true, "", nil
+}
+
+// bytesProcessed returns the total number of bytes of a bucket source, and true when all of them have been
+// processed. Until the total is known, the detected size from the source status is used.
+func bytesProcessed(downloaded int64, total *int64, status *openapi.Status) (int64, bool) {
+	if total != nil {
+		return *total, downloaded >= *total
+	}
+	if detected, ok := status.GetDetectedSizeBytesOk(); ok {
+		return *detected, downloaded >= *detected
+	}
+
+	return 0, false
+}
+
+// sourcesInState is met when every source is in the state.
+func sourcesInState(state string) readinessCondition {
+	return func(_ context.Context, _ *rockset.RockClient, c openapi.Collection) (bool, string, error) {
+		var pending []string
+		for i, src := range c.Sources {
+			if current := src.Status.GetState(); current != state {
+				pending = append(pending, fmt.Sprintf("source %d is %s", i, current))
+			}
+		}
+
+		if len(pending) > 0 {
+			return false, fmt.Sprintf("waiting for sources to be %s: %s", state, strings.Join(pending, ", ")), nil
+		}
+
+		return true, "", nil
+	}
+}
+
+// kafkaOffsetLagBelow is met when the total offset lag of the Kafka sources is below the threshold.
+func kafkaOffsetLagBelow(threshold int64) readinessCondition {
+	return func(_ context.Context, _ *rockset.RockClient, c openapi.Collection) (bool, string, error) {
+		var kafka, partitions int
+		var lag int64
+		for _, src := range c.Sources {
+			if src.Kafka == nil {
+				continue
+			}
+			kafka++
+
+			status := src.Kafka.GetStatus()
+			for _, p := range status.GetKafkaPartitions() {
+				partitions++
+				lag += p.GetOffsetLag()
+			}
+		}
+
+		switch {
+		case kafka == 0:
+			return false, "", fmt.Errorf("kafka_offset_lag_below requires a Kafka source")
+		case partitions == 0:
+			return false, "no Kafka partitions reported yet", nil
+		case lag >= threshold:
+			return false, fmt.Sprintf("Kafka offset lag is %d, waiting for it to be below %d", lag, threshold), nil
+		}
+
+		return true, "", nil
+	}
+}
+
+// queryIsTrue is met when the query returns true.
+func queryIsTrue(sql string) readinessCondition {
+	return func(ctx context.Context, rc *rockset.RockClient, _ openapi.Collection) (bool, string, error) {
+		resp, err := rc.Query(ctx, sql)
+		if client.IsPermanent(err) {
+			// e.g. a syntax error or missing permissions, which waiting doesn't resolve
+			return false, "", fmt.Errorf("wait_for query: %w", err)
+		}
+		if err != nil {
+			// the collection might not be queryable yet, so the query is retried until the timeout
+			tflog.Debug(ctx, "wait_for query failed", map[string]interface{}{"error": err.Error()})
+			return false, fmt.Sprintf("query failed: %v", err), nil
+		}
+
+		ready, err := singleBool(resp.Results)
+		if err != nil {
+			return false, "", fmt.Errorf("wait_for query: %w", err)
+		}
+		if !ready {
+			return false, "query returned false", nil
+		}
+
+		return true, "", nil
+	}
+}
+
+// singleBool returns the value of a query result which must be one row with one boolean column.
+func singleBool(results []map[string]interface{}) (bool, error) {
+	if len(results) != 1 {
+		return false, fmt.Errorf("expected 1 row, got %d", len(results))
+	}
+	if len(results[0]) != 1 {
+		return false, fmt.Errorf("expected 1 column, got %d", len(results[0]))
+	}
+
+	for column, value := range results[0] {
+		b, ok := value.(bool)
+		if !ok {
+			return false, fmt.Errorf("expected column %s to be a boolean, got %T", column, value)
+		}
+		return b, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,258 @@
+package rockset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestReadinessConditions(t *testing.T) {
+	scanning := openapi.Source{Dynamodb: &openapi.SourceDynamoDb{
+		TableName: "orders",
+		Status: &openapi.StatusDynamoDb{
+			ScanRecordsProcessed: openapi.PtrInt64(10),
+			ScanTotalRecords:     openapi.PtrInt64(20),
+		},
+	}}
+	scanned := openapi.Source{Mongodb: &openapi.SourceMongoDb{
+		CollectionName: "users",
+		Status:         &openapi.StatusMongoDb{ScanEndTime: openapi.PtrString("2024-01-01T00:00:00Z")},
+	}}
+	downloading := openapi.Source{
+		S3: &openapi.SourceS3{
+			Bucket:                "bucket",
+			ObjectBytesDownloaded: openapi.PtrInt64(100),
+		},
+		Status: &openapi.Status{State: openapi.PtrString("WATCHING"), DetectedSizeBytes: openapi.PtrInt64(200)},
+	}
+	downloaded := openapi.Source{
+		Gcs: &openapi.SourceGcs{
+			Bucket:                openapi.PtrString("bucket"),
+			ObjectBytesDownloaded: openapi.PtrInt64(200),
+			ObjectBytesTotal:      openapi.PtrInt64(200),
+		},
+		Status: &openapi.Status{State: openapi.PtrString("WATCHING")},
+	}
+	kafka := func(lags ...int64) openapi.Source {
+		partitions := make([]openapi.StatusKafkaPartition, len(lags))
+		for i, lag := range lags {
+			partitions[i] = openapi.StatusKafkaPartition{OffsetLag: openapi.PtrInt64(lag)}
+		}
+		return openapi.Source{
+			Kafka:  &openapi.SourceKafka{Status: &openapi.StatusKafka{KafkaPartitions: partitions}},
+			Status: &openapi.Status{State: openapi.PtrString("INITIALIZING")},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		condition readinessCondition
+		sources   []openapi.Source
+		ready     bool
+		progress  string
+		err       string
+	}{
+		{
+			name:      "initial ingest done",
+			condition: initialIngestDone,
+			sources:   []openapi.Source{scanned, downloaded, kafka(10)},
+			ready:     true,
+		},
+		{
+			name:      "initial ingest in progress",
+			condition: initialIngestDone,
+			sources:   []openapi.Source{scanning, scanned, downloading},
+			progress: "initial ingest: DynamoDB table orders scanned 10 of 20 records, " +
+				"S3 bucket bucket processed 100 of 200 bytes",
+		},
+		{
+			name:      "sources in state",
+			condition: sourcesInState("WATCHING"),
+			sources:   []openapi.Source{downloading, downloaded},
+			ready:     true,
+		},
+		{
+			name:      "source not in state",
+			condition: sourcesInState("WATCHING"),
+			sources:   []openapi.Source{downloaded, kafka(1)},
+			progress:  "waiting for sources to be WATCHING: source 1 is INITIALIZING",
+		},
+		{
+			name:      "kafka caught up",
+			condition: kafkaOffsetLagBelow(10),
+			sources:   []openapi.Source{kafka(4, 5)},
+			ready:     true,
+		},
+		{
+			name:      "kafka lagging",
+			condition: kafkaOffsetLagBelow(10),
+			sources:   []openapi.Source{kafka(5), kafka(5)},
+			progress:  "Kafka offset lag is 10, waiting for it to be below 10",
+		},
+		{
+			name:      "kafka without partitions",
+			condition: kafkaOffsetLagBelow(10),
+			sources:   []openapi.Source{kafka()},
+			progress:  "no Kafka partitions reported yet",
+		},
+		{
+			name:      "kafka lag without kafka source",
+			condition: kafkaOffsetLagBelow(10),
+			sources:   []openapi.Source{downloaded},
+			err:       "kafka_offset_lag_below requires a Kafka source",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			ready, progress, err := tst.condition(context.TODO(), nil, openapi.Collection{Sources: tst.sources})
+			if tst.err != "" {
+				assert.EqualError(t, err, tst.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tst.ready, ready)
+			assert.Equal(t, tst.progress, progress)
+		})
+	}
+}
+
+func TestSingleBool(t *testing.T) {
+	b, err := singleBool([]map[string]interface{}{{"ready": true}})
+	require.NoError(t, err)
+	assert.True(t, b)
+
+	_, err = singleBool(nil)
+	assert.EqualError(t, err, "expected 1 row, got 0")
+
+	_, err = singleBool([]map[string]interface{}{{"a": true, "b": false}})
+	assert.EqualError(t, err, "expected 1 column, got 2")
+
+	_, err = singleBool([]map[string]interface{}{{"count": float64(1)}})
+	assert.EqualError(t, err, "expected column count to be a boolean, got float64")
+}
+
+func TestQueryIsTrue(t *testing.T) {
+	status := http.StatusNotFound
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message": "query failed"}`))
+	}))
+	defer ts.Close()
+
+	rc, err := rockset.NewClient(rockset.WithAPIServer(ts.URL), rockset.WithAPIKey("key"),
+		rockset.WithHTTPClient(ts.Client()))
+	require.NoError(t, err)
+	condition := queryIsTrue("SELECT COUNT(*) > 0 FROM commons.orders")
+
+	// the collection might not be queryable yet
+	ready, progress, err := condition(context.TODO(), rc, openapi.Collection{})
+	require.NoError(t, err)
+	assert.False(t, ready)
+	assert.Contains(t, progress, "query failed")
+
+	// while e.g. a syntax error won't go away by waiting
+	status = http.StatusBadRequest
+	_, _, err = condition(context.TODO(), rc, openapi.Collection{})
+	assert.ErrorContains(t, err, "wait_for query: ")
+}
+
+func TestValidateWaitFor(t *testing.T) {
+	lag := []interface{}{map[string]interface{}{"kafka_offset_lag_below": 10}}
+	kafka := []interface{}{map[string]interface{}{"integration_name": "kafka", "topic_name": "orders"}}
+
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		config   map[string]interface{}
+		err      bool
+	}{
+		{
+			name:     "kafka",
+			resource: resourceKafkaCollection(),
+			config:   map[string]interface{}{"source": kafka, "wait_for": lag},
+		},
+		{
+			name:     "kafka without sources",
+			resource: resourceKafkaCollection(),
+			config:   map[string]interface{}{"wait_for": lag},
+			err:      true,
+		},
+		{
+			name:     "not kafka",
+			resource: resourceCollection(),
+			config:   map[string]interface{}{"wait_for": lag},
+			err:      true,
+		},
+		{
+			name:     "no lag",
+			resource: resourceCollection(),
+			config: map[string]interface{}{"wait_for": []interface{}{
+				map[string]interface{}{"source_state": "WATCHING"},
+			}},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			tst.config["workspace"] = "commons"
+			tst.config["name"] = "orders"
+
+			_, err := tst.resource.Diff(context.TODO(), nil, terraform.NewResourceConfigRaw(tst.config), nil)
+			if tst.err {
+				assert.ErrorContains(t, err, "kafka_offset_lag_below requires a Kafka source")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWaitForReadiness(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	_, err := rc.CreateCollection(ctx, "commons", "orders", option.WithCollectionRequest(openapi.CreateCollectionRequest{
+		Name:    openapi.PtrString("orders"),
+		Sources: []openapi.Source{{S3: &openapi.SourceS3{Bucket: "orders"}}},
+	}))
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceS3Collection().Schema, map[string]interface{}{
+		"name":      "orders",
+		"workspace": "commons",
+		"wait_for": []interface{}{
+			map[string]interface{}{"source_state": "WATCHING"},
+		},
+	})
+	conditions := readinessConditions(d)
+	require.Len(t, conditions, 1)
+
+	// the fake moves the sources to WATCHING once the collection is ready
+	require.NoError(t, waitForReadiness(ctx, rc, d, "commons", "orders", conditions))
+
+	c, err := rc.GetCollection(ctx, "commons", "orders")
+	require.NoError(t, err)
+	assert.Equal(t, "WATCHING", c.Sources[0].Status.GetState())
+
+	d = schema.TestResourceDataRaw(t, resourceS3Collection().Schema, map[string]interface{}{
+		"name":      "orders",
+		"workspace": "commons",
+	})
+	assert.Empty(t, readinessConditions(d))
+}
@@ -0,0 +1,80 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client"
+)
+
+// withReplacementWarning makes a plan which replaces the collection warn about how much data it holds.
+func withReplacementWarning(r *schema.Resource) *schema.Resource {
+	addCustomizeDiff(r, warnCollectionReplacement(r))
+
+	return r
+}
+
+func warnCollectionReplacement(r *schema.Resource) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+		attributes := replacedBy(d, r)
+		if len(attributes) == 0 {
+			return nil
+		}
+
+		summary := fmt.Sprintf("collection %s will be replaced", d.Id())
+		cause := fmt.Sprintf("Changing %s forces the collection to be replaced", strings.Join(attributes, ", "))
+
+		rc, ok := meta.(*rockset.RockClient)
+		if !ok {
+			addPlanWarning(ctx, summary, cause+".")
+			return nil
+		}
+
+		workspace, name := workspaceAndNameFromID(d.Id())
+		c, err := rc.GetCollection(ctx, workspace, name)
+		if err != nil {
+			// the warning is only informative, so it mustn't fail the plan
+			tflog.Warn(ctx, "failed to get the size of the collection", map[string]interface{}{
+				"workspace": workspace,
+				"name":      name,
+				"error":     err.Error(),
+			})
+			addPlanWarning(ctx, summary, cause+", the size of its data couldn't be determined.")
+			return nil
+		}
+
+		stats := c.GetStats()
+		data := fmt.Sprintf("%d documents (%s)", stats.GetDocCount(), byteSize(stats.GetTotalSize()))
+		if len(c.Sources) == 0 {
+			addPlanWarning(ctx, summary, fmt.Sprintf("%s, which deletes its %s. "+
+				"It has no sources, so documents added through the write API are lost.", cause, data))
+		} else {
+			addPlanWarning(ctx, summary, fmt.Sprintf("%s, which deletes its %s and re-ingests them from its sources.",
+				cause, data))
+		}
+
+		return nil
+	}
+}
+
+// byteSize formats n using binary units.
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
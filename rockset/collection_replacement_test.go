@@ -0,0 +1,74 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestWarnCollectionReplacement(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	_, err := rc.CreateCollection(ctx, "commons", "orders")
+	require.NoError(t, err)
+	_, err = rc.AddDocuments(ctx, "commons", "orders", []interface{}{
+		map[string]interface{}{"id": 1},
+		map[string]interface{}{"id": 2},
+		map[string]interface{}{"id": 3},
+	})
+	require.NoError(t, err)
+
+	state := &terraform.InstanceState{
+		ID: "commons.orders",
+		Attributes: map[string]string{
+			"id":                  "commons.orders",
+			"name":                "orders",
+			"workspace":           "commons",
+			"description":         "orders",
+			"retention_secs":      "0",
+			"deletion_protection": "false",
+			"wait_for_collection": "true",
+			"wait_for_documents":  "0",
+		},
+	}
+	config := func(retention int) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":           "orders",
+			"workspace":      "commons",
+			"description":    "orders",
+			"retention_secs": retention,
+		})
+	}
+
+	warnings := &planDiagnostics{}
+	_, err = resourceCollection().Diff(context.WithValue(ctx, planDiagnosticsKey{}, warnings), state, config(3600), rc)
+	require.NoError(t, err)
+	require.Len(t, warnings.diags, 1)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, warnings.diags[0].Severity)
+	assert.Equal(t, "collection commons.orders will be replaced", warnings.diags[0].Summary)
+	assert.Equal(t, "Changing retention_secs forces the collection to be replaced, which deletes its 3 documents "+
+		"(3.0 KiB). It has no sources, so documents added through the write API are lost.", warnings.diags[0].Detail)
+
+	warnings = &planDiagnostics{}
+	_, err = resourceCollection().Diff(context.WithValue(ctx, planDiagnosticsKey{}, warnings), state, config(0), rc)
+	require.NoError(t, err)
+	assert.Empty(t, warnings.diags)
+}
+
+func TestByteSize(t *testing.T) {
+	assert.Equal(t, "0 B", byteSize(0))
+	assert.Equal(t, "1023 B", byteSize(1023))
+	assert.Equal(t, "1.0 KiB", byteSize(1024))
+	assert.Equal(t, "1.5 MiB", byteSize(3<<19))
+	assert.Equal(t, "2.0 TiB", byteSize(2<<40))
+}
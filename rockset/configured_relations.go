@@ -0,0 +1,180 @@
+package rockset
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/rockset/terraform-provider-rockset/internal/sqlscan"
+)
+
+// relationResources are the resources which SQL can reference.
+var relationResources = []string{
+	"rockset_alias",
+	"rockset_view",
+	"rockset_collection",
+	"rockset_dynamodb_collection",
+	"rockset_gcs_collection",
+	"rockset_kafka_collection",
+	"rockset_kinesis_collection",
+	"rockset_mongodb_collection",
+	"rockset_s3_collection",
+}
+
+// configuredRelation is a collection, alias or view in the configuration. The workspace or name is empty if it isn't
+// a literal value, e.g. if it is a variable, and then matches any workspace or name.
+type configuredRelation struct {
+	workspace string
+	name      string
+}
+
+func (r configuredRelation) matches(ref sqlscan.Reference) bool {
+	return (r.workspace == "" || r.workspace == ref.Workspace) && (r.name == "" || r.name == ref.Name)
+}
+
+// configuredRelations are the collections, aliases and views in the Terraform configuration of the working
+// directory, and of the modules it installed, so references to those which are created by the same apply aren't
+// reported as missing. The configuration is read instead of recording the resources as they are planned, as
+// Terraform plans resources which don't depend on each other in any order.
+type configuredRelations struct {
+	dir       string
+	once      sync.Once
+	relations []configuredRelation
+}
+
+// has returns true if the reference might be created by the configuration.
+func (c *configuredRelations) has(ctx context.Context, ref sqlscan.Reference) bool {
+	c.once.Do(func() { c.relations = readConfiguredRelations(ctx, c.dir) })
+
+	for _, r := range c.relations {
+		if r.matches(ref) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readConfiguredRelations reads the relationResources from the configuration files in dir and its modules. Files
+// which can't be read are skipped, as Terraform reports the errors in them.
+func readConfiguredRelations(ctx context.Context, dir string) []configuredRelation {
+	parser := hclparse.NewParser()
+
+	var relations []configuredRelation
+	for _, module := range moduleDirs(ctx, dir) {
+		files, _ := filepath.Glob(filepath.Join(module, "*.tf"))
+		jsonFiles, _ := filepath.Glob(filepath.Join(module, "*.tf.json"))
+
+		for _, path := range append(files, jsonFiles...) {
+			var f *hcl.File
+			var diags hcl.Diagnostics
+			if strings.HasSuffix(path, ".json") {
+				f, diags = parser.ParseJSONFile(path)
+			} else {
+				f, diags = parser.ParseHCLFile(path)
+			}
+			if diags.HasErrors() {
+				tflog.Debug(ctx, "skipping configuration file which can't be parsed", map[string]interface{}{
+					"path":  path,
+					"error": diags.Error(),
+				})
+				continue
+			}
+
+			relations = append(relations, fileRelations(f)...)
+		}
+	}
+
+	return relations
+}
+
+func fileRelations(f *hcl.File) []configuredRelation {
+	content, _, _ := f.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "resource", LabelNames: []string{"type", "name"}}},
+	})
+	if content == nil {
+		return nil
+	}
+
+	var relations []configuredRelation
+	for _, b := range content.Blocks {
+		if !slices.Contains(relationResources, b.Labels[0]) {
+			continue
+		}
+
+		attrs, _, _ := b.Body.PartialContent(&hcl.BodySchema{
+			Attributes: []hcl.AttributeSchema{{Name: "workspace"}, {Name: "name"}},
+		})
+		if attrs == nil {
+			continue
+		}
+		relations = append(relations, configuredRelation{
+			workspace: literalString(attrs.Attributes["workspace"]),
+			name:      literalString(attrs.Attributes["name"]),
+		})
+	}
+
+	return relations
+}
+
+// literalString returns the value of the attribute if it is a string which doesn't reference anything.
+func literalString(attr *hcl.Attribute) string {
+	if attr == nil {
+		return ""
+	}
+
+	v, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() || v.IsNull() || !v.Type().Equals(cty.String) {
+		return ""
+	}
+
+	return v.AsString()
+}
+
+// moduleDirs returns dir and the directories of the modules which Terraform installed in it.
+func moduleDirs(ctx context.Context, dir string) []string {
+	dataDir := os.Getenv("TF_DATA_DIR")
+	if dataDir == "" {
+		dataDir = ".terraform"
+	}
+	if !filepath.IsAbs(dataDir) {
+		dataDir = filepath.Join(dir, dataDir)
+	}
+
+	dirs := []string{dir}
+
+	data, err := os.ReadFile(filepath.Join(dataDir, "modules", "modules.json"))
+	if err != nil {
+		return dirs
+	}
+
+	var manifest struct {
+		Modules []struct {
+			Dir string `json:"Dir"`
+		} `json:"Modules"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		tflog.Debug(ctx, "skipping the modules, as their manifest can't be parsed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return dirs
+	}
+
+	for _, m := range manifest.Modules {
+		module := filepath.Join(dir, m.Dir)
+		if !slices.Contains(dirs, module) {
+			dirs = append(dirs, module)
+		}
+	}
+
+	return dirs
+}
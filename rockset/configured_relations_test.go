@@ -0,0 +1,75 @@
+package rockset
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/sqlscan"
+)
+
+// chdir changes the working directory to dir until the test is done.
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+}
+
+func TestConfiguredRelations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.tf": `
+resource "rockset_collection" "orders" {
+  workspace = "commons"
+  name      = "orders"
+}
+
+resource "rockset_view" "recent" {
+  workspace = "analytics"
+  name      = var.view
+}
+
+resource "rockset_workspace" "reports" {
+  name = "reports"
+}
+
+module "events" {
+  source = "./modules/events"
+}
+`,
+		"alias.tf.json": `{"resource": {"rockset_alias": {"latest": {"workspace": "aliases", "name": "latest"}}}}`,
+		"broken.tf":     `resource "rockset_collection" "broken" {`,
+		"modules/events/main.tf": `
+resource "rockset_s3_collection" "events" {
+  workspace = "${var.env}_events"
+  name      = "clicks"
+}
+`,
+		".terraform/modules/modules.json": `{"Modules":[{"Key":"","Source":"","Dir":"."},` +
+			`{"Key":"events","Source":"./modules/events","Dir":"modules/events"}]}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	configured := &configuredRelations{dir: dir}
+	tests := map[sqlscan.Reference]bool{
+		{Workspace: "commons", Name: "orders"}:     true,
+		{Workspace: "commons", Name: "customers"}:  false,
+		{Workspace: "analytics", Name: "anything"}: true,
+		{Workspace: "aliases", Name: "latest"}:     true,
+		{Workspace: "prod_events", Name: "clicks"}: true,
+		{Workspace: "reports", Name: "reports"}:    false,
+		{Workspace: "commons", Name: "broken"}:     false,
+	}
+	for ref, expected := range tests {
+		assert.Equal(t, expected, configured.has(context.TODO(), ref), ref.String())
+	}
+}
@@ -0,0 +1,61 @@
+package rockset
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// replacementPredicate returns true if the planned change of an attribute replaces the object, for attributes which
+// only force a replacement for some changes, so they can't be ForceNew.
+type replacementPredicate func(d *schema.ResourceDiff) bool
+
+var (
+	replacementPredicatesMu sync.Mutex
+	replacementPredicates   = make(map[*schema.Resource]map[string]replacementPredicate)
+)
+
+// addReplacementPredicate registers that changing the attribute of the resource replaces it when f returns true.
+func addReplacementPredicate(r *schema.Resource, attribute string, f replacementPredicate) {
+	replacementPredicatesMu.Lock()
+	defer replacementPredicatesMu.Unlock()
+
+	if replacementPredicates[r] == nil {
+		replacementPredicates[r] = make(map[string]replacementPredicate)
+	}
+	replacementPredicates[r][attribute] = f
+}
+
+func replacementPredicateOf(r *schema.Resource, attribute string) replacementPredicate {
+	replacementPredicatesMu.Lock()
+	defer replacementPredicatesMu.Unlock()
+
+	return replacementPredicates[r][attribute]
+}
+
+// replacedBy returns the sorted configurable attributes which force the object to be replaced by changing, either as
+// they are ForceNew, or as their replacementPredicate says so.
+func replacedBy(d *schema.ResourceDiff, r *schema.Resource) []string {
+	var attributes []string
+	for k, v := range r.Schema {
+		if !(v.Optional || v.Required) || !d.HasChange(k) {
+			continue
+		}
+		if replaces := replacementPredicateOf(r, k); v.ForceNew || (replaces != nil && replaces(d)) {
+			attributes = append(attributes, k)
+		}
+	}
+	sort.Strings(attributes)
+
+	return attributes
+}
+
+// addCustomizeDiff makes the resource run f after any CustomizeDiff it already has.
+func addCustomizeDiff(r *schema.Resource, f schema.CustomizeDiffFunc) {
+	if r.CustomizeDiff != nil {
+		f = customdiff.All(r.CustomizeDiff, f)
+	}
+	r.CustomizeDiff = f
+}
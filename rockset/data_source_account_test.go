@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccAccount_Basic(t *testing.T) {
+	useCassette(t)
+
 	resourceName := "data.rockset_account.test"
 
 	resource.Test(t, resource.TestCase{
@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccQueryLambdaTag_Data(t *testing.T) {
+	useCassette(t)
+
 	resourceName := "data.rockset_query_lambda_tag.test"
 
 	resource.Test(t, resource.TestCase{
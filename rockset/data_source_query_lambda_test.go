@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccQueryLambda_Data(t *testing.T) {
+	useCassette(t)
+
 	resourceName := "data.rockset_query_lambda.test"
 
 	resource.Test(t, resource.TestCase{
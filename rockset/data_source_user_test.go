@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccDataUser_Basic(t *testing.T) {
+	useCassette(t)
+
 	user := "data.rockset_user.pme"
 	current := "data.rockset_user.current"
 
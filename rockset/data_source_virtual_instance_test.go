@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccVirtualInstance_Data(t *testing.T) {
+	useCassette(t)
+
 	resourceName := "data.rockset_virtual_instance.main"
 
 	resource.Test(t, resource.TestCase{
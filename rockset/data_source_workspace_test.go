@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccDataWorkspace_Basic(t *testing.T) {
+	useCassette(t)
+
 	resourceName := "data.rockset_workspace.test"
 
 	resource.Test(t, resource.TestCase{
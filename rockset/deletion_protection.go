@@ -0,0 +1,65 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// withDeletionProtection adds the deletion_protection attribute to the resource, which when enabled makes both
+// deleting and replacing the object fail. As the value in the state is used, it must be disabled in a separate
+// apply before the object can be destroyed.
+func withDeletionProtection(kind string, r *schema.Resource) *schema.Resource {
+	r.Schema["deletion_protection"] = &schema.Schema{
+		Description: fmt.Sprintf("Prevent the %s from being destroyed or replaced. "+
+			"It must be disabled in a separate apply before the %[1]s can be destroyed.", kind),
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+
+	addCustomizeDiff(r, preventProtectedReplacement(kind, r))
+
+	del := r.DeleteContext
+	r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		if d.Get("deletion_protection").(bool) {
+			return diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("%s %s has deletion protection enabled", kind, d.Id()),
+				Detail: fmt.Sprintf("Set deletion_protection to false and apply before destroying the %s.",
+					kind),
+				AttributePath: cty.GetAttrPath("deletion_protection"),
+			}}
+		}
+
+		return del(ctx, d, meta)
+	}
+
+	return r
+}
+
+// preventProtectedReplacement fails the plan when a protected object would be replaced, and names the attributes
+// which force the replacement.
+func preventProtectedReplacement(kind string, r *schema.Resource) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.Id() == "" {
+			return nil
+		}
+		if protected, _ := d.GetChange("deletion_protection"); !protected.(bool) {
+			return nil
+		}
+
+		attributes := replacedBy(d, r)
+		if len(attributes) == 0 {
+			return nil
+		}
+
+		return fmt.Errorf("%s %s has deletion protection enabled, but changing %s requires replacing it; "+
+			"set deletion_protection to false and apply before making this change",
+			kind, d.Id(), strings.Join(attributes, ", "))
+	}
+}
@@ -0,0 +1,98 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletionProtection_Delete(t *testing.T) {
+	r := resourceS3Collection()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"name":                "orders",
+		"workspace":           "commons",
+		"deletion_protection": true,
+	})
+	d.SetId("commons.orders")
+
+	// the client is never used, as the delete is refused before the API is called
+	diags := r.DeleteContext(context.TODO(), d, nil)
+	require.True(t, diags.HasError())
+	assert.Equal(t, "collection commons.orders has deletion protection enabled", diags[0].Summary)
+}
+
+func TestDeletionProtection_Replace(t *testing.T) {
+	state := func(protected string) *terraform.InstanceState {
+		return &terraform.InstanceState{
+			ID: "commons.orders",
+			Attributes: map[string]string{
+				"id":                  "commons.orders",
+				"name":                "orders",
+				"workspace":           "commons",
+				"description":         "orders",
+				"deletion_protection": protected,
+				"wait_for_collection": "true",
+				"wait_for_documents":  "0",
+			},
+		}
+	}
+	config := func(name, description string, protected bool) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":                name,
+			"workspace":           "commons",
+			"description":         description,
+			"deletion_protection": protected,
+		})
+	}
+
+	tests := []struct {
+		name   string
+		state  *terraform.InstanceState
+		config *terraform.ResourceConfig
+		err    string
+	}{
+		{
+			name:   "replace protected",
+			state:  state("true"),
+			config: config("renamed", "orders", true),
+			err: "collection commons.orders has deletion protection enabled, but changing name requires " +
+				"replacing it; set deletion_protection to false and apply before making this change",
+		},
+		{
+			name:   "disable protection while replacing",
+			state:  state("true"),
+			config: config("renamed", "orders", false),
+			err:    "changing name requires replacing it",
+		},
+		{
+			name:   "update protected",
+			state:  state("true"),
+			config: config("orders", "updated", true),
+		},
+		{
+			name:   "replace unprotected",
+			state:  state("false"),
+			config: config("renamed", "orders", true),
+		},
+		{
+			name:   "create",
+			config: config("orders", "orders", true),
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			_, err := resourceCollection().Diff(context.TODO(), tst.state, tst.config, nil)
+			if tst.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tst.err)
+		})
+	}
+}
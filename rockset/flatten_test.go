@@ -0,0 +1,274 @@
+package rockset
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/flatten")
+
+// flattenPath contains a directory per conversion with the API payloads, and a golden file per payload with
+// the flattened state and what it expands to.
+const flattenPath = "../testdata/flatten"
+
+type conversion struct {
+	resource *schema.Resource
+	// flatten decodes the API payload and sets it in the resource data, like the read function of the resource
+	flatten func(ctx context.Context, payload []byte, d *schema.ResourceData) error
+	// expand converts the resource data back to the API representation
+	expand func(d *schema.ResourceData) (interface{}, error)
+	// field is the field of the payload the expanded value is compared to, or empty for the whole payload
+	field string
+}
+
+func flattenCollection(parse func(context.Context, *openapi.Collection, *schema.ResourceData) error) func(
+	context.Context, []byte, *schema.ResourceData) error {
+	return func(ctx context.Context, payload []byte, d *schema.ResourceData) error {
+		var c openapi.Collection
+		if err := json.Unmarshal(payload, &c); err != nil {
+			return err
+		}
+		if err := parseBaseCollection(&c, d); err != nil {
+			return err
+		}
+
+		return parse(ctx, &c, d)
+	}
+}
+
+var conversions = map[string]conversion{
+	"s3_collection": {
+		resource: resourceS3Collection(),
+		flatten: flattenCollection(func(ctx context.Context, c *openapi.Collection, d *schema.ResourceData) error {
+			return parseBucketCollection(ctx, "s3", c, d)
+		}),
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return makeBucketSourceParams("s3", d.Get("source"))
+		},
+		field: "sources",
+	},
+	"gcs_collection": {
+		resource: resourceGCSCollection(),
+		flatten: flattenCollection(func(ctx context.Context, c *openapi.Collection, d *schema.ResourceData) error {
+			return parseBucketCollection(ctx, "gcs", c, d)
+		}),
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return makeBucketSourceParams("gcs", d.Get("source"))
+		},
+		field: "sources",
+	},
+	"kafka_collection": {
+		resource: resourceKafkaCollection(),
+		flatten: flattenCollection(func(_ context.Context, c *openapi.Collection, d *schema.ResourceData) error {
+			return parseKafkaCollection(c, d)
+		}),
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return expandKafkaSourceParams(d.Get("source")), nil
+		},
+		field: "sources",
+	},
+	"dynamodb_collection": {
+		resource: resourceDynamoDBCollection(),
+		flatten: flattenCollection(func(_ context.Context, c *openapi.Collection, d *schema.ResourceData) error {
+			return parseDynamoDBCollection(c, d)
+		}),
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return makeSourceParams(d.Get("source")), nil
+		},
+		field: "sources",
+	},
+	"role": {
+		resource: resourceRole(),
+		flatten: func(_ context.Context, payload []byte, d *schema.ResourceData) error {
+			var r openapi.Role
+			if err := json.Unmarshal(payload, &r); err != nil {
+				return err
+			}
+
+			return d.Set("privilege", flattenRolePrivileges(r.Privileges))
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return expandRolePrivileges(d.Get("privilege"))
+		},
+		field: "privileges",
+	},
+	"query_lambda": {
+		resource: resourceQueryLambda(),
+		flatten: func(_ context.Context, payload []byte, d *schema.ResourceData) error {
+			var sql openapi.QueryLambdaSql
+			if err := json.Unmarshal(payload, &sql); err != nil {
+				return err
+			}
+
+			return d.Set("sql", flattenQueryLambdaSQL(&sql))
+		},
+		expand: func(d *schema.ResourceData) (interface{}, error) {
+			return makeQueryLambdaSQL(d.Get("sql")), nil
+		},
+	},
+}
+
+// golden is the content of a golden file.
+type golden struct {
+	State    map[string]string `json:"state"`
+	Expanded interface{}       `json:"expanded"`
+}
+
+// TestFlattenExpand flattens every payload in testdata/flatten, and compares the state and the result of expanding
+// it again to the golden file. Run it with -update to write the golden files after a deliberate change.
+func TestFlattenExpand(t *testing.T) {
+	for name, c := range conversions {
+		payloads, err := filepath.Glob(filepath.Join(flattenPath, name, "*.json"))
+		require.NoError(t, err)
+		require.NotEmpty(t, payloads, "no payloads for %s", name)
+
+		for _, p := range payloads {
+			c, p := c, p
+			t.Run(name+"/"+strings.TrimSuffix(filepath.Base(p), ".json"), func(t *testing.T) {
+				testConversion(t, c, p)
+			})
+		}
+	}
+}
+
+func testConversion(t *testing.T, c conversion, payloadFile string) {
+	payload, err := os.ReadFile(payloadFile)
+	require.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, c.resource.Schema, map[string]interface{}{})
+	d.SetId("test")
+	require.NoError(t, c.flatten(context.TODO(), payload, d))
+
+	expanded, err := c.expand(d)
+	require.NoError(t, err)
+
+	actual := golden{State: d.State().Attributes, Expanded: toJSONValue(t, expanded)}
+
+	// expand(flatten(x)) must agree with x on everything it sets, or the provider would change what it read
+	var full interface{}
+	require.NoError(t, json.Unmarshal(payload, &full))
+	if c.field != "" {
+		full = full.(map[string]interface{})[c.field]
+	}
+	assert.True(t, jsonContains(full, actual.Expanded), "expanded value doesn't match the payload:\n%s",
+		marshalIndent(t, actual.Expanded))
+
+	goldenFile := strings.TrimSuffix(payloadFile, ".json") + ".golden"
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(goldenFile, append(marshalIndent(t, actual), '\n'), 0o644))
+		return
+	}
+
+	data, err := os.ReadFile(goldenFile)
+	require.NoError(t, err, "run the test with -update to create the golden file")
+	assert.JSONEq(t, string(data), string(marshalIndent(t, actual)))
+}
+
+// toJSONValue converts v to the generic representation encoding/json decodes it as.
+func toJSONValue(t *testing.T, v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	var out interface{}
+	require.NoError(t, json.Unmarshal(data, &out))
+
+	return out
+}
+
+func marshalIndent(t *testing.T, v interface{}) []byte {
+	data, err := json.MarshalIndent(v, "", "  ")
+	require.NoError(t, err)
+
+	return data
+}
+
+// jsonContains returns true if every value in sub is in full. The elements of lists may be in any order, as sets
+// don't keep the order of the payload, and full may contain elements which aren't in sub. Fields which are missing
+// in full match zero values in sub, as the API omits them.
+func jsonContains(full, sub interface{}) bool {
+	if full == nil && isZeroJSON(sub) {
+		return true
+	}
+
+	switch s := sub.(type) {
+	case map[string]interface{}:
+		f, ok := full.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range s {
+			if !jsonContains(f[k], v) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		f, ok := full.([]interface{})
+		if !ok {
+			return false
+		}
+		used := make([]bool, len(f))
+	elements:
+		for _, v := range s {
+			for i := range f {
+				if !used[i] && jsonContains(f[i], v) {
+					used[i] = true
+					continue elements
+				}
+			}
+			return false
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(full, sub)
+	}
+}
+
+func isZeroJSON(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, e := range v {
+			if !isZeroJSON(e) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return len(v) == 0
+	case nil:
+		return true
+	default:
+		return reflect.ValueOf(v).IsZero()
+	}
+}
+
+func TestJSONContains(t *testing.T) {
+	full := map[string]interface{}{
+		"a": "x",
+		"b": []interface{}{map[string]interface{}{"c": 1.0}, map[string]interface{}{"c": 2.0, "d": true}},
+	}
+
+	assert.True(t, jsonContains(full, map[string]interface{}{"a": "x"}))
+	assert.True(t, jsonContains(full, map[string]interface{}{
+		"b": []interface{}{map[string]interface{}{"c": 2.0}, map[string]interface{}{"c": 1.0}},
+	}))
+	assert.False(t, jsonContains(full, map[string]interface{}{"a": "y"}))
+	assert.False(t, jsonContains(full, map[string]interface{}{"e": "x"}))
+	assert.True(t, jsonContains(full, map[string]interface{}{"e": false, "f": []interface{}{}}))
+	assert.False(t, jsonContains(full, map[string]interface{}{
+		"b": []interface{}{map[string]interface{}{"c": 1.0}, map[string]interface{}{"c": 1.0}},
+	}))
+}
@@ -0,0 +1,144 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// idPart is one part of a composite resource ID, which is stored in attribute.
+type idPart struct {
+	attribute string
+	// validate is optional, and is called with the value of the part
+	validate schema.SchemaValidateFunc
+}
+
+// idFormat describes how a resource ID is composed, so the ID given to terraform import can be validated before the
+// resource is read.
+type idFormat struct {
+	parts     []idPart
+	separator string
+	// optional is the number of trailing parts which can be left out
+	optional int
+	// example is an ID of the expected form, which is included in the error when an ID can't be parsed
+	example string
+}
+
+// nameIDFormat is the format of resources which are identified by their name.
+func nameIDFormat(example string) idFormat {
+	return idFormat{parts: []idPart{{"name", rocksetNameValidator}}, example: example}
+}
+
+// workspaceNameIDFormat is the format of resources which are identified by their workspace and name, see toID.
+func workspaceNameIDFormat(example string) idFormat {
+	return idFormat{
+		parts:     []idPart{{"workspace", rocksetNameValidator}, {"name", rocksetNameValidator}},
+		separator: ".",
+		example:   example,
+	}
+}
+
+// String returns the format with the attribute names as placeholders, e.g. <workspace>.<name>
+func (f idFormat) String() string {
+	parts := make([]string, len(f.parts))
+	for i, p := range f.parts {
+		parts[i] = "<" + p.attribute + ">"
+		if i >= len(f.parts)-f.optional {
+			parts[i] = "[" + f.separator + parts[i] + "]"
+		} else if i > 0 {
+			parts[i] = f.separator + parts[i]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+// parse splits the id into its parts and validates each of them. The last part keeps any further separators.
+func (f idFormat) parse(id string) ([]string, error) {
+	fields := []string{id}
+	if f.separator != "" {
+		fields = strings.SplitN(id, f.separator, len(f.parts))
+	}
+
+	if len(fields) < len(f.parts)-f.optional {
+		return nil, f.errorf(id, "missing %s", f.parts[len(fields)].attribute)
+	}
+
+	for i, field := range fields {
+		p := f.parts[i]
+		if field == "" {
+			return nil, f.errorf(id, "%s is empty", p.attribute)
+		}
+		if p.validate == nil {
+			continue
+		}
+		if _, errs := p.validate(field, p.attribute); len(errs) > 0 {
+			return nil, f.errorf(id, "%v", errors.Join(errs...))
+		}
+	}
+
+	return fields, nil
+}
+
+func (f idFormat) errorf(id, format string, a ...any) error {
+	if f.example == "" {
+		return fmt.Errorf("invalid import id %q: %s, expected an id of the form %s", id, fmt.Sprintf(format, a...), f)
+	}
+
+	return fmt.Errorf("invalid import id %q: %s, expected an id of the form %s, e.g. %s",
+		id, fmt.Sprintf(format, a...), f, f.example)
+}
+
+// importer returns a resource importer which validates the import id against the format, sets the attributes the id
+// is composed of, and reads the resource so importing an object which doesn't exist fails with a clear error.
+func importer(format idFormat, read schema.ReadContextFunc) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			id := d.Id()
+
+			parts, err := format.parse(id)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, part := range parts {
+				if attribute := format.parts[i].attribute; attribute != "id" {
+					if err = d.Set(attribute, part); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			if err = diagsToError(read(ctx, d, meta)); err != nil {
+				return nil, err
+			}
+
+			if d.Id() == "" {
+				return nil, fmt.Errorf("cannot import %s: it doesn't exist", id)
+			}
+
+			return []*schema.ResourceData{d}, nil
+		},
+	}
+}
+
+// diagsToError returns the errors in diags as one error, or nil if there are none.
+func diagsToError(diags diag.Diagnostics) error {
+	var errs []error
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		if d.Detail == "" {
+			errs = append(errs, errors.New(d.Summary))
+		} else {
+			errs = append(errs, fmt.Errorf("%s: %s", d.Summary, d.Detail))
+		}
+	}
+
+	return errors.Join(errs...)
+}
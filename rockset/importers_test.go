@@ -0,0 +1,155 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestIDFormat(t *testing.T) {
+	apiKey := idFormat{
+		parts:     []idPart{{attribute: "name"}, {attribute: "user"}},
+		separator: ":",
+		optional:  1,
+	}
+
+	assert.Equal(t, "<workspace>.<name>", workspaceNameIDFormat("").String())
+	assert.Equal(t, "<name>", nameIDFormat("").String())
+	assert.Equal(t, "<name>[:<user>]", apiKey.String())
+
+	parts, err := apiKey.parse("key")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, parts)
+
+	parts, err = apiKey.parse("key:user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key", "user@example.com"}, parts)
+
+	_, err = apiKey.parse("key:")
+	assert.EqualError(t, err, `invalid import id "key:": user is empty, expected an id of the form <name>[:<user>]`)
+
+	apiKey.example = "my-key:user@example.com"
+	_, err = apiKey.parse("key:")
+	assert.EqualError(t, err, `invalid import id "key:": user is empty, expected an id of the form <name>[:<user>], `+
+		`e.g. my-key:user@example.com`)
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.TODO()
+	const defaultVI = "29e4a43c-fff4-4fe6-80e3-1ee57bc22e82"
+	srv := rocksettest.NewServer(rocksettest.WithDefaultVirtualInstanceID(defaultVI))
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	_, err := rc.CreateCollection(ctx, "commons", "imported")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		id       string
+		err      string
+		want     map[string]string
+	}{
+		{
+			name:     "workspace",
+			resource: resourceWorkspace(),
+			id:       "commons",
+			want:     map[string]string{"name": "commons"},
+		},
+		{
+			name:     "missing workspace",
+			resource: resourceWorkspace(),
+			id:       "missing",
+			err:      `cannot import missing: it doesn't exist`,
+		},
+		{
+			name:     "collection",
+			resource: resourceCollection(),
+			id:       "commons.imported",
+			want:     map[string]string{"workspace": "commons", "name": "imported"},
+		},
+		{
+			name:     "collection without workspace",
+			resource: resourceS3Collection(),
+			id:       "imported",
+			err: `invalid import id "imported": missing name, expected an id of the form <workspace>.<name>, ` +
+				`e.g. commons.my_collection`,
+		},
+		{
+			name:     "collection with empty name",
+			resource: resourceCollection(),
+			id:       "commons.",
+			err:      `invalid import id "commons.": name is empty`,
+		},
+		{
+			name:     "invalid workspace name",
+			resource: resourceView(),
+			id:       "my workspace.view",
+			err:      `invalid import id "my workspace.view": workspace must start with alphanumeric`,
+		},
+		{
+			name:     "query lambda tag without tag",
+			resource: resourceQueryLambdaTag(),
+			id:       "commons.ql",
+			err: `invalid import id "commons.ql": missing name, expected an id of the form ` +
+				`<workspace>.<query_lambda>.<name>, e.g. commons.my_query_lambda.latest`,
+		},
+		{
+			name:     "mount without virtual instance",
+			resource: resourceCollectionMount(),
+			id:       "commons.imported",
+			err:      `invalid import id "commons.imported": missing virtual_instance_id`,
+		},
+		{
+			name:     "virtual instance",
+			resource: resourceVirtualInstance(),
+			id:       defaultVI,
+			want:     map[string]string{"id": defaultVI, "name": "main"},
+		},
+		{
+			name:     "auto scaling policy",
+			resource: resourceAutoScalingPolicy(),
+			id:       defaultVI,
+			want:     map[string]string{"virtual_instance_id": defaultVI},
+		},
+		{
+			name:     "api key",
+			resource: resourceApiKey(),
+			id:       "admin:" + rocksettest.AdminEmail,
+			want:     map[string]string{"name": "admin", "user": rocksettest.AdminEmail},
+		},
+		{
+			name:     "role",
+			resource: resourceRole(),
+			id:       "read-only",
+			want:     map[string]string{"name": "read-only"},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			d := tst.resource.TestResourceData()
+			d.SetId(tst.id)
+
+			imported, err := tst.resource.Importer.StateContext(ctx, d, rc)
+			if tst.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tst.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, imported, 1)
+
+			for attribute, value := range tst.want {
+				assert.Equal(t, value, imported[0].Get(attribute), attribute)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package rockset
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// resolveClient makes the resources and data sources receive a *rockset.RockClient as meta, while the provider
+// is configured with a *client.Lazy, so the client only is created when a resource needs it.
+func resolveClient(p *schema.Provider) {
+	var unknown bool
+
+	configure := p.ConfigureContextFunc
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		unknown = !d.GetRawConfig().IsWhollyKnown()
+		return configure(ctx, d)
+	}
+
+	for _, r := range p.ResourcesMap {
+		r.CreateContext = withClient(r.CreateContext)
+		r.ReadContext = withClient(r.ReadContext)
+		r.UpdateContext = withClient(r.UpdateContext)
+		r.DeleteContext = withClient(r.DeleteContext)
+
+		if customize := r.CustomizeDiff; customize != nil {
+			r.CustomizeDiff = func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+				rc, err := metaClient(ctx, meta)
+				if err != nil {
+					if !unknown {
+						return err
+					}
+					// the credentials are unknown until apply, so checks which need the client are skipped
+					tflog.Debug(ctx, "planning without a client", map[string]interface{}{"error": err.Error()})
+					return customize(ctx, diff, nil)
+				}
+				return customize(ctx, diff, rc)
+			}
+		}
+
+		if r.Importer != nil && r.Importer.StateContext != nil {
+			importer := r.Importer.StateContext
+			r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData,
+				meta interface{}) ([]*schema.ResourceData, error) {
+				rc, err := metaClient(ctx, meta)
+				if err != nil {
+					return nil, err
+				}
+				return importer(ctx, d, rc)
+			}
+		}
+	}
+
+	for _, r := range p.DataSourcesMap {
+		r.ReadContext = withClient(r.ReadContext)
+	}
+}
+
+// withClient wraps fn so it is called with the client instead of the *client.Lazy.
+func withClient[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](fn F) F {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		rc, err := metaClient(ctx, meta)
+		if err != nil {
+			return DiagFromErr(err)
+		}
+
+		return fn(ctx, d, rc)
+	}
+}
+
+// metaClient returns the client from meta, and creates it if it is the first time it is needed.
+func metaClient(ctx context.Context, meta interface{}) (interface{}, error) {
+	lazy, ok := meta.(*client.Lazy)
+	if !ok {
+		// the provider hasn't been configured, or meta already is a *rockset.RockClient
+		return meta, nil
+	}
+
+	rc, err := lazy.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
@@ -0,0 +1,64 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// testLazyProvider returns a resource of a provider configured with apiKey, whose client always fails to be created,
+// and the meta its CustomizeDiff was called with.
+func testLazyProvider(t *testing.T, apiKey cty.Value) (*schema.Resource, interface{}, *interface{}) {
+	var customized interface{} = "not called"
+
+	p := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"api_key": {Type: schema.TypeString, Optional: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"rockset_test": {
+				CustomizeDiff: func(_ context.Context, _ *schema.ResourceDiff, meta interface{}) error {
+					customized = meta
+					return nil
+				},
+			},
+		},
+		ConfigureContextFunc: func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return client.NewLazy(client.Config{Region: "usw2a1", APIServer: "api.usw2a1.rockset.com"}), nil
+		},
+	}
+	resolveClient(p)
+
+	block := schema.InternalMap(p.Schema).CoreConfigSchema()
+	c := terraform.NewResourceConfigShimmed(cty.ObjectVal(map[string]cty.Value{"api_key": apiKey}), block)
+	c.CtyValue = cty.ObjectVal(map[string]cty.Value{"api_key": apiKey})
+	require.False(t, p.Configure(context.TODO(), c).HasError())
+
+	return p.ResourcesMap["rockset_test"], p.Meta(), &customized
+}
+
+func TestResolveClient_CustomizeDiff(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("unknown config", func(t *testing.T) {
+		r, meta, customized := testLazyProvider(t, cty.UnknownVal(cty.String))
+
+		require.NoError(t, r.CustomizeDiff(ctx, nil, meta))
+		assert.Nil(t, *customized)
+	})
+
+	t.Run("known config", func(t *testing.T) {
+		r, meta, customized := testLazyProvider(t, cty.StringVal("key"))
+
+		assert.ErrorContains(t, r.CustomizeDiff(ctx, nil, meta), "only one of api_server and region can be set")
+		assert.Equal(t, "not called", *customized)
+	})
+}
@@ -0,0 +1,70 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+// TestReadNotFound verifies that resources which were deleted outside of terraform are removed from the state when
+// they are read, so the next plan recreates them instead of failing.
+func TestReadNotFound(t *testing.T) {
+	ctx := context.TODO()
+	const defaultVI = "29e4a43c-fff4-4fe6-80e3-1ee57bc22e82"
+	srv := rocksettest.NewServer(rocksettest.WithDefaultVirtualInstanceID(defaultVI))
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+		id       string
+	}{
+		{"workspace", resourceWorkspace(), "missing"},
+		{"collection", resourceCollection(), "commons.missing"},
+		{"dynamodb collection", resourceDynamoDBCollection(), "commons.missing"},
+		{"virtual instance", resourceVirtualInstance(), "00000000-0000-0000-0000-000000000000"},
+		{"auto scaling policy", resourceAutoScalingPolicy(), "00000000-0000-0000-0000-000000000000"},
+		{"mount of missing virtual instance", resourceCollectionMount(),
+			mountToID("commons._events", "00000000-0000-0000-0000-000000000000")},
+		{"unmounted collection", resourceCollectionMount(), mountToID("commons._events", defaultVI)},
+		{"scheduled lambda", resourceScheduledLambda(), "commons.rrn:sl:usw2a1:missing"},
+		{"view", resourceView(), "commons.missing"},
+		{"alias", resourceAlias(), "commons.missing"},
+		{"query lambda", resourceQueryLambda(), "commons.missing"},
+		{"role", resourceRole(), "missing"},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			d := tst.resource.TestResourceData()
+			d.SetId(tst.id)
+
+			diags := tst.resource.ReadContext(ctx, d, rc)
+			require.False(t, diags.HasError(), "%v", diags)
+			assert.Empty(t, d.Id())
+		})
+	}
+}
+
+func TestReadNotFound_OtherErrors(t *testing.T) {
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv, rockset.WithAPIKey("invalid"))
+
+	r := resourceWorkspace()
+	d := r.TestResourceData()
+	d.SetId("commons")
+
+	diags := r.ReadContext(context.TODO(), d, rc)
+	assert.True(t, diags.HasError())
+	assert.Equal(t, "commons", d.Id())
+}
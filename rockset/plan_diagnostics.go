@@ -0,0 +1,83 @@
+package rockset
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+)
+
+// ProviderServer returns the SDKv2 provider as a gRPC server, which adds the diagnostics raised while planning
+// to the plan. A CustomizeDiff function can only return an error, so this is the only way to show warnings,
+// or to attach an error to an attribute.
+func ProviderServer() tfprotov5.ProviderServer {
+	return planDiagnosticsServer{ProviderServer: Provider().GRPCProvider()}
+}
+
+type planDiagnosticsServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s planDiagnosticsServer) PlanResourceChange(ctx context.Context,
+	req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	collected := &planDiagnostics{}
+
+	resp, err := s.ProviderServer.PlanResourceChange(context.WithValue(ctx, planDiagnosticsKey{}, collected), req)
+	if resp != nil {
+		resp.Diagnostics = append(resp.Diagnostics, collected.diags...)
+	}
+
+	return resp, err
+}
+
+type planDiagnosticsKey struct{}
+
+// planDiagnostics collects the diagnostics of a single plan request.
+type planDiagnostics struct {
+	diags []*tfprotov5.Diagnostic
+}
+
+// addPlanDiagnostic adds d to the plan, and returns false if not planning through ProviderServer.
+// The SDK can run CustomizeDiff twice for the same plan, so a diagnostic with the same summary for the same
+// attribute is only added once, as e.g. the trace ID in the detail differs between two API calls.
+func addPlanDiagnostic(ctx context.Context, d *tfprotov5.Diagnostic) bool {
+	collected, ok := ctx.Value(planDiagnosticsKey{}).(*planDiagnostics)
+	if !ok {
+		return false
+	}
+
+	for _, existing := range collected.diags {
+		if existing.Severity == d.Severity && existing.Summary == d.Summary &&
+			(existing.Attribute == nil) == (d.Attribute == nil) &&
+			(d.Attribute == nil || existing.Attribute.Equal(d.Attribute)) {
+			return true
+		}
+	}
+	collected.diags = append(collected.diags, d)
+
+	return true
+}
+
+// addPlanWarning shows a warning in the plan. When not planning through ProviderServer the warning only is logged.
+func addPlanWarning(ctx context.Context, summary, detail string) {
+	tflog.Warn(ctx, summary, map[string]interface{}{"detail": detail})
+
+	addPlanDiagnostic(ctx, &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  summary,
+		Detail:   detail,
+	})
+}
+
+// planError fails the plan with err, attached to the attribute the SQL came from if err is about the SQL.
+// It returns the error which CustomizeDiff must return, which is nil when the error was added to the plan,
+// as the SDK would otherwise add it a second time without the attribute.
+func planError(ctx context.Context, err error, sql ...diagnostics.SQL) error {
+	if addPlanDiagnostic(ctx, diagnostics.Proto(err, sql...)) {
+		return nil
+	}
+
+	return err
+}
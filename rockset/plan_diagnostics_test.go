@@ -0,0 +1,48 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+)
+
+type planningServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (planningServer) PlanResourceChange(ctx context.Context,
+	_ *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	// CustomizeDiff runs twice when a resource is replaced
+	for i := 0; i < 2; i++ {
+		addPlanWarning(ctx, "summary", "detail")
+		if err := planError(ctx, errors.New("invalid"), diagnostics.SQL{Path: []interface{}{"query"}}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &tfprotov5.PlanResourceChangeResponse{}, nil
+}
+
+func TestPlanDiagnosticsServer(t *testing.T) {
+	s := planDiagnosticsServer{ProviderServer: planningServer{}}
+
+	resp, err := s.PlanResourceChange(context.TODO(), &tfprotov5.PlanResourceChangeRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Diagnostics, 2)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, resp.Diagnostics[0].Severity)
+	assert.Equal(t, "summary", resp.Diagnostics[0].Summary)
+	assert.Equal(t, "detail", resp.Diagnostics[0].Detail)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[1].Severity)
+	assert.Equal(t, "invalid", resp.Diagnostics[1].Summary)
+}
+
+func TestPlanErrorWithoutServer(t *testing.T) {
+	err := planError(context.TODO(), errors.New("invalid"))
+	assert.EqualError(t, err, "invalid")
+}
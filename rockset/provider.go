@@ -2,29 +2,34 @@ package rockset
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"net/http"
-	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
-	"github.com/rockset/rockset-go-client"
-	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+	"github.com/rockset/terraform-provider-rockset/internal/sqlscan"
 )
 
 type Config struct {
-	APIKey    string
-	APIServer string
-	OrgID     string
+	APIKey          string
+	APIServer       string
+	Region          string
+	OrgID           string
+	APIKeyCommand   string
+	Profile         string
+	CredentialsFile string
+	Retry           client.RetryConfig
 }
 
 func Provider() *schema.Provider {
 	schema.DescriptionKind = schema.StringMarkdown
-	return &schema.Provider{
+	p := &schema.Provider{
 		ResourcesMap: map[string]*schema.Resource{
 			"rockset_alias":                resourceAlias(),
 			"rockset_api_key":              resourceApiKey(),
@@ -74,6 +79,15 @@ func Provider() *schema.Provider {
 				Default:     "",
 				Description: "The API server for accessing Rockset",
 			},
+			"region": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+				Description: "The Rockset region to connect to, which is used to look up the API server. " +
+					"Can't be used together with `api_server`. If neither is set, the API server is discovered from " +
+					"the clusters of the organization. Valid regions are: " + strings.Join(client.RegionIDs(), ", "),
+				ValidateFunc: validation.StringInSlice(client.RegionIDs(), false),
+			},
 			"organization_id": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -82,61 +96,172 @@ func Provider() *schema.Provider {
 					"If this is set, the provider will validate that the organization_id matches the organization_id " +
 					"of the api key. If it does not match, the provider will return an error.\n",
 			},
+			"api_key_command": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+				Description: "A command which is run using the shell to fetch the API key, " +
+					"e.g. from a password manager. The output of the command is used as the API key. " +
+					"Can't be used together with `api_key`.",
+			},
+			"profile": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+				Description: "The name of the context in the credentials file to read the API key, API server and " +
+					"organization ID from. Arguments set in the provider block take precedence over the profile. " +
+					"If not present it will be sourced from the `ROCKSET_PROFILE` environment variable.",
+			},
+			"credentials_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+				Description: "The credentials file to read the `profile` from. " +
+					"Defaults to `~/.config/rockset/credentials.yaml`, " +
+					"or the `ROCKSET_CREDENTIALS_FILE` environment variable if it is set.",
+			},
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Prevents the provider from creating, updating or deleting any resources, " +
+					"which fail before any API call is made. Reads and data sources still work, " +
+					"so it can be used to run `terraform plan` with an API key which never should change anything.",
+			},
+			"skip_sql_validation": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: "Don't send the SQL of views and query lambdas to Rockset for " +
+					"validation when planning, e.g. for plans which can't reach the API server. The SQL is still " +
+					"checked offline, e.g. for query lambda parameters without a default_parameter, and the collections, " +
+					"aliases and views it references are still looked up, to warn about those which don't exist.",
+			},
+			"max_retries": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Description: "Maximum number of times an API call which failed with a retryable error, " +
+					"e.g. HTTP 429 or 503, is retried. Defaults to 5.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"min_backoff": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "How long to wait before the first retry of an API call, " +
+					"which is doubled for every subsequent retry. Defaults to `1s`.",
+				ValidateFunc: durationValidator,
+			},
+			"max_backoff": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Description: "The longest time to wait between two retries of an API call, " +
+					"unless the API asks for a longer wait using the `Retry-After` header. Defaults to `30s`.",
+				ValidateFunc: durationValidator,
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Maximum number of concurrent API calls. Defaults to no limit.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"requests_per_second": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Description:  "Maximum number of API calls per second. Defaults to no limit.",
+				ValidateFunc: validation.FloatAtLeast(0),
+			},
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
+	validateSQLAtPlan(p)
+	resolveClient(p)
+	protectReadOnly(p)
+	traceOperations(p)
+
+	return p
 }
 
-func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	retry, err := retryConfig(d)
+	if err != nil {
+		return nil, DiagFromErr(err)
+	}
+
 	config := Config{
-		APIKey:    d.Get("api_key").(string),
-		APIServer: d.Get("api_server").(string),
-		OrgID:     d.Get("organization_id").(string),
+		APIKey:          d.Get("api_key").(string),
+		APIServer:       d.Get("api_server").(string),
+		Region:          d.Get("region").(string),
+		OrgID:           d.Get("organization_id").(string),
+		APIKeyCommand:   d.Get("api_key_command").(string),
+		Profile:         d.Get("profile").(string),
+		CredentialsFile: d.Get("credentials_file").(string),
+		Retry:           retry,
 	}
 
-	return config.Client()
+	return config.Client(ctx)
 }
 
-const providerUserAgent = "terraform-provider-rockset"
+// retryConfig reads the retry settings from the provider configuration, and uses the default for those not set.
+func retryConfig(d *schema.ResourceData) (client.RetryConfig, error) {
+	cfg := client.DefaultRetryConfig()
 
-func (c *Config) Client() (interface{}, diag.Diagnostics) {
-	var diags diag.Diagnostics
-	var opts = []rockset.RockOption{
-		rockset.WithUserAgent(fmt.Sprintf("%s/%s", providerUserAgent, Version)),
+	if isConfigured(d, "max_retries") {
+		cfg.MaxRetries = d.Get("max_retries").(int)
 	}
-
-	if c.APIKey != "" {
-		opts = append(opts, rockset.WithAPIKey(c.APIKey))
+	if isConfigured(d, "min_backoff") {
+		backoff, err := time.ParseDuration(d.Get("min_backoff").(string))
+		if err != nil {
+			return cfg, err
+		}
+		cfg.MinBackoff = backoff
 	}
-
-	if c.APIServer != "" {
-		opts = append(opts, rockset.WithAPIServer(c.APIServer))
+	if isConfigured(d, "max_backoff") {
+		backoff, err := time.ParseDuration(d.Get("max_backoff").(string))
+		if err != nil {
+			return cfg, err
+		}
+		cfg.MaxBackoff = backoff
 	}
+	cfg.MaxConcurrentRequests = d.Get("max_concurrent_requests").(int)
+	cfg.RequestsPerSecond = d.Get("requests_per_second").(float64)
 
-	if debug := os.Getenv("ROCKSET_DEBUG"); debug == "true" {
-		opts = append(opts, rockset.WithHTTPDebug())
-	}
+	return cfg, nil
+}
 
-	rc, err := rockset.NewClient(opts...)
-	if err != nil {
-		return nil, DiagFromErr(err)
+// isConfigured checks if key is set in the provider configuration, which unlike d.GetOk() also is true
+// when it is set to the zero value.
+func isConfigured(d *schema.ResourceData, key string) bool {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return false
 	}
 
-	// if we have an org id in the config, validate that it matches the org id of the api key
-	if c.OrgID != "" {
-		org, err := rc.GetOrganization(context.Background())
-		if err != nil {
-			return nil, DiagFromErr(err)
-		}
+	v := raw.GetAttr(key)
+	return v.IsKnown() && !v.IsNull()
+}
 
-		if org.GetId() != c.OrgID {
-			return nil, diag.Errorf(
-				"the organization configured in the provider `%s` does not match the organization of the api key: `%s`",
-				c.OrgID, org.GetId())
-		}
-	}
+// Client returns the *client.Lazy for the configuration, which is shared with the plugin framework half of the
+// provider, and creates the Rockset client the first time a resource or data source needs it.
+func (c *Config) Client(_ context.Context) (interface{}, diag.Diagnostics) {
+	return client.Shared(client.Config{
+		APIKey:          c.APIKey,
+		APIServer:       c.APIServer,
+		Region:          c.Region,
+		OrgID:           c.OrgID,
+		APIKeyCommand:   c.APIKeyCommand,
+		Profile:         c.Profile,
+		CredentialsFile: c.CredentialsFile,
+		Version:         Version,
+		Retry:           c.Retry,
+	}), nil
+}
 
-	return rc, diags
+// durationValidator validates that the value can be parsed using time.ParseDuration()
+func durationValidator(val interface{}, key string) ([]string, []error) {
+	if _, err := time.ParseDuration(val.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s must be a duration, e.g. 10s: %v", key, err)}
+	}
+	return nil, nil
 }
 
 const nameRe = "[[:alnum:]][[:alnum:]-_]*"
@@ -225,15 +350,16 @@ func mergeSchemas(mergeOnto map[string]*schema.Schema, toMerge map[string]*schem
 	return mergeOnto
 }
 
+// suppressEquivalentSQL suppresses the diff of SQL which only differs in formatting, as Rockset returns the SQL
+// re-formatted.
+func suppressEquivalentSQL(_, old, new string, _ *schema.ResourceData) bool {
+	return sqlscan.Equivalent(old, new)
+}
+
 // checkForNotFoundError check is the error is a Rockset NotFoundError, and then clears the id which makes
 // terraform create the resource, but if it isn't a NotFoundError it will return the error wrapped in diag.Diagnostics
 func checkForNotFoundError(d *schema.ResourceData, err error) diag.Diagnostics {
-	var re rockerr.Error
-	if !errors.As(err, &re) {
-		return DiagFromErr(err)
-	}
-
-	if !re.IsNotFoundError() {
+	if !client.IsNotFound(err) {
 		return DiagFromErr(err)
 	}
 
@@ -242,49 +368,8 @@ func checkForNotFoundError(d *schema.ResourceData, err error) diag.Diagnostics {
 	return diag.Diagnostics{}
 }
 
-func DiagFromErr(err error) diag.Diagnostics {
-	if err == nil {
-		return nil
-	}
-	d := diag.Diagnostic{
-		Severity: diag.Error,
-		Summary:  err.Error(),
-	}
-
-	var re rockerr.Error
-	if errors.As(err, &re) {
-		var sb strings.Builder
-		var msgs []string
-
-		sb.WriteString(re.GetMessage())
-		sb.WriteString(": ")
-
-		if t, ok := re.GetTypeOk(); ok {
-			msgs = append(msgs, fmt.Sprintf("Error Type: %s", *t))
-		}
-		if re.StatusCode != 0 {
-			msgs = append(msgs, fmt.Sprintf("HTTP status code (%d) %s", re.StatusCode, http.StatusText(re.StatusCode)))
-		}
-		if re.GetTraceId() != "" {
-			msgs = append(msgs, fmt.Sprintf("Trace ID: %s", re.GetTraceId()))
-		}
-		if re.GetErrorId() != "" {
-			msgs = append(msgs, fmt.Sprintf("Error ID: %s", re.GetErrorId()))
-		}
-		if re.GetQueryId() != "" {
-			msgs = append(msgs, fmt.Sprintf("Query ID: %s", re.GetQueryId()))
-		}
-		if re.HasLine() {
-			msgs = append(msgs, fmt.Sprintf("Line: %d", re.GetLine()))
-		}
-		if re.HasColumn() {
-			msgs = append(msgs, fmt.Sprintf("Column: %d", re.GetColumn()))
-		}
-
-		sb.WriteString(strings.Join(msgs, ", "))
-
-		d.Detail = sb.String()
-	}
-
-	return diag.Diagnostics{d}
+// DiagFromErr returns err as diagnostics. If the API call which failed sent SQL, the SQL and the attribute it came
+// from can be passed, so errors in the SQL point at the attribute.
+func DiagFromErr(err error, sql ...diagnostics.SQL) diag.Diagnostics {
+	return diagnostics.SDK(err, sql...)
 }
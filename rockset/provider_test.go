@@ -22,9 +22,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/rs/zerolog"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
 )
 
 var testAccProviderFactories map[string]func() (*schema.Provider, error)
@@ -41,6 +47,63 @@ func init() {
 	}
 
 	testCtx = createTestContext()
+
+	if os.Getenv("ROCKSET_FAKE_API") == "true" {
+		startFakeAPI()
+	}
+	setupCassettes()
+}
+
+// startFakeAPI runs the acceptance tests against an in-process fake of the Rockset API, seeded with the resources
+// the tests expect to exist in the organization.
+func startFakeAPI() {
+	srv := rocksettest.NewServer(rocksettest.WithDefaultVirtualInstanceID("29e4a43c-fff4-4fe6-80e3-1ee57bc22e82"))
+	client.BaseTransport = srv.Client().Transport
+	os.Setenv(rockset.APIKeyEnvironmentVariableName, rocksettest.APIKey)
+	os.Setenv(rockset.APIServerEnvironmentVariableName, srv.URL)
+
+	rc, err := rockset.NewClient(rockset.WithHTTPClient(srv.Client()))
+	if err != nil {
+		panic(fmt.Sprintf("failed to create client for the fake Rockset API: %v", err))
+	}
+
+	err = func() error {
+		for _, ws := range []string{"acc", "persistent"} {
+			if _, err := rc.CreateWorkspace(testCtx, ws); err != nil {
+				return err
+			}
+		}
+		for _, c := range []string{"persistent.snp", "persistent.patch"} {
+			ws, name, _ := strings.Cut(c, ".")
+			if _, err := rc.CreateCollection(testCtx, ws, name); err != nil {
+				return err
+			}
+		}
+
+		ql, err := rc.CreateQueryLambda(testCtx, "persistent", "events", "SELECT * FROM commons._events LIMIT 1")
+		if err != nil {
+			return err
+		}
+		if _, err = rc.CreateQueryLambdaTag(testCtx, "persistent", "events", ql.GetVersion(), "test"); err != nil {
+			return err
+		}
+
+		_, err = rc.CreateUser(testCtx, "pme+readonly@rockset.com", []string{"read-only"})
+		return err
+	}()
+	if err != nil {
+		panic(fmt.Sprintf("failed to seed the fake Rockset API: %v", err))
+	}
+}
+
+// testAccClient returns the client of the configured testAccProvider.
+func testAccClient() *rockset.RockClient {
+	rc, err := testAccProvider.Meta().(*client.Lazy).Client(testCtx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create Rockset client: %v", err))
+	}
+
+	return rc
 }
 
 func TestProvider(t *testing.T) {
@@ -84,15 +147,82 @@ func TestToBoolPtrNilIfEmpty_Error(t *testing.T) {
 	}))
 }
 
+// configureProvider configures the provider with the attributes in config, and returns the ResourceData
+// passed to the ConfigureContextFunc.
+func configureProvider(t *testing.T, config map[string]cty.Value) *schema.ResourceData {
+	var data *schema.ResourceData
+
+	p := Provider()
+	p.ConfigureContextFunc = func(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		data = d
+		return nil, nil
+	}
+
+	block := schema.InternalMap(p.Schema).CoreConfigSchema()
+	attrs := make(map[string]cty.Value)
+	for name, attr := range block.Attributes {
+		attrs[name] = cty.NullVal(attr.Type)
+	}
+	for name, v := range config {
+		attrs[name] = v
+	}
+
+	// set the raw config like the gRPC provider server does, so GetRawConfig() works
+	c := terraform.NewResourceConfigShimmed(cty.ObjectVal(attrs), block)
+	c.CtyValue = cty.ObjectVal(attrs)
+
+	diags := p.Configure(context.TODO(), c)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return data
+}
+
+func TestRetryConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		d := configureProvider(t, nil)
+
+		cfg, err := retryConfig(d)
+		require.NoError(t, err)
+		assert.Equal(t, client.DefaultRetryConfig(), cfg)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		d := configureProvider(t, map[string]cty.Value{
+			"max_retries":             cty.NumberIntVal(0),
+			"min_backoff":             cty.StringVal("500ms"),
+			"max_backoff":             cty.StringVal("1m"),
+			"max_concurrent_requests": cty.NumberIntVal(4),
+			"requests_per_second":     cty.NumberFloatVal(2.5),
+		})
+
+		cfg, err := retryConfig(d)
+		require.NoError(t, err)
+		assert.Equal(t, client.RetryConfig{
+			MaxRetries:            0,
+			MinBackoff:            500 * time.Millisecond,
+			MaxBackoff:            time.Minute,
+			MaxConcurrentRequests: 4,
+			RequestsPerSecond:     2.5,
+		}, cfg)
+	})
+}
+
 // testAccPreCheck verifies required environment variables are set before running tests.
 // It always requires ROCKSET_APIKEY and ROCKSET_APISERVER.
 // Fails early if they are not set.
 func testAccPreCheck(t *testing.T, env ...string) {
 	env = append(env, "ROCKSET_APIKEY", "ROCKSET_APISERVER")
 	for _, e := range env {
+		if vcrMode == vcrReplay {
+			// the values were redacted from the cassettes
+			setDefaultEnv(e, vcrRedacted)
+		}
 		if _, found := os.LookupEnv(e); !found {
 			t.Fatalf("%s must be set for acceptance tests", e)
 		}
+		if vcr != nil && strings.HasPrefix(e, "TF_VAR_") {
+			vcr.secret(os.Getenv(e))
+		}
 	}
 }
 
@@ -181,7 +311,14 @@ func randomString(length int) string {
 	return stringWithCharset(length, charset)
 }
 
+// testNamePrefix is the prefix of the names randomName generates, which the sweepers use to find leaked objects.
+const testNamePrefix = "tf_"
+
 func randomName(prefix string) string {
+	if vcr != nil && vcrMode == vcrReplay {
+		return vcr.name(prefix, "")
+	}
+
 	num, found := os.LookupEnv(buildNum)
 	if !found {
 		if user, found := os.LookupEnv("USER"); found {
@@ -191,12 +328,17 @@ func randomName(prefix string) string {
 		}
 	}
 
-	return fmt.Sprintf("tf_%s_%s_%s", num, prefix, randomString(6))
+	name := fmt.Sprintf("%s%s_%s_%s", testNamePrefix, num, prefix, randomString(6))
+	if vcr != nil {
+		vcr.name(prefix, name)
+	}
+
+	return name
 }
 
 func description() string {
 	num, found := os.LookupEnv(buildNum)
-	if !found {
+	if !found || vcr != nil {
 		num = "dev"
 	}
 	return fmt.Sprintf("created by terraform integration test run %s", num)
@@ -238,10 +380,38 @@ func createTestContext() context.Context {
 	return l.WithContext(context.Background())
 }
 
+// testAccStoreID stores the ID of the resource, so a later step can use it.
+func testAccStoreID(name string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, found := s.RootModule().Resources[name]
+		if !found {
+			return fmt.Errorf("resource %s not found", name)
+		}
+		*id = rs.Primary.ID
+
+		return nil
+	}
+}
+
+// testAccDeletedStep deletes a resource outside of terraform, and verifies that planning the config again
+// proposes to recreate it instead of failing to read it.
+func testAccDeletedStep(t *testing.T, config string, del func(context.Context, *rockset.RockClient) error) resource.TestStep {
+	return resource.TestStep{
+		PreConfig: func() {
+			if err := del(testCtx, testAccClient()); err != nil {
+				t.Fatalf("failed to delete resource outside of terraform: %v", err)
+			}
+		},
+		Config:             config,
+		PlanOnly:           true,
+		ExpectNonEmptyPlan: true,
+	}
+}
+
 // testAccCheckRocksetIntegrationDestroy checks that an integration has been destroyed
 func testAccCheckRocksetIntegrationDestroy(resource string) func(*terraform.State) error {
 	return func(s *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		for _, rs := range s.RootModule().Resources {
 			if rs.Type != resource {
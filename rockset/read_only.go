@@ -0,0 +1,64 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// protectReadOnly makes the create, update and delete functions of all resources fail before making any API call
+// when the provider is configured with read_only = true. Reads and data sources are unaffected.
+func protectReadOnly(p *schema.Provider) {
+	var readOnly bool
+
+	configure := p.ConfigureContextFunc
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		readOnly = d.Get("read_only").(bool)
+		return configure(ctx, d)
+	}
+
+	check := func(name, operation string) diag.Diagnostics {
+		if !readOnly {
+			return nil
+		}
+
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("can't %s %s: %s", operation, name, client.ErrReadOnly),
+			Detail:   "Set read_only = false in the provider configuration to allow changes.",
+		}}
+	}
+
+	for name, r := range p.ResourcesMap {
+		name := name
+
+		if create := r.CreateContext; create != nil {
+			r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				if diags := check(name, "create"); diags != nil {
+					return diags
+				}
+				return create(ctx, d, meta)
+			}
+		}
+		if update := r.UpdateContext; update != nil {
+			r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				if diags := check(name, "update"); diags != nil {
+					return diags
+				}
+				return update(ctx, d, meta)
+			}
+		}
+		if del := r.DeleteContext; del != nil {
+			r.DeleteContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+				if diags := check(name, "delete"); diags != nil {
+					return diags
+				}
+				return del(ctx, d, meta)
+			}
+		}
+	}
+}
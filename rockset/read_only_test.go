@@ -0,0 +1,71 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReadOnlyProvider(t *testing.T, readOnly bool) (*schema.Resource, *int) {
+	var calls int
+	count := func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		calls++
+		return nil
+	}
+
+	p := &schema.Provider{
+		Schema: map[string]*schema.Schema{
+			"read_only": {Type: schema.TypeBool, Optional: true},
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"rockset_test": {
+				CreateContext: count,
+				ReadContext:   count,
+				UpdateContext: count,
+				DeleteContext: count,
+			},
+		},
+		ConfigureContextFunc: func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
+			return nil, nil
+		},
+	}
+	protectReadOnly(p)
+
+	diags := p.Configure(context.TODO(), terraform.NewResourceConfigRaw(map[string]interface{}{"read_only": readOnly}))
+	require.False(t, diags.HasError())
+
+	return p.ResourcesMap["rockset_test"], &calls
+}
+
+func TestProtectReadOnly(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("read only", func(t *testing.T) {
+		r, calls := testReadOnlyProvider(t, true)
+		d := r.TestResourceData()
+
+		diags := r.CreateContext(ctx, d, nil)
+		require.True(t, diags.HasError())
+		assert.Equal(t, "can't create rockset_test: the provider is configured with read_only = true", diags[0].Summary)
+
+		assert.True(t, r.UpdateContext(ctx, d, nil).HasError())
+		assert.True(t, r.DeleteContext(ctx, d, nil).HasError())
+		assert.False(t, r.ReadContext(ctx, d, nil).HasError())
+		assert.Equal(t, 1, *calls, "only read should be called")
+	})
+
+	t.Run("read write", func(t *testing.T) {
+		r, calls := testReadOnlyProvider(t, false)
+		d := r.TestResourceData()
+
+		assert.False(t, r.CreateContext(ctx, d, nil).HasError())
+		assert.False(t, r.UpdateContext(ctx, d, nil).HasError())
+		assert.False(t, r.DeleteContext(ctx, d, nil).HasError())
+		assert.Equal(t, 3, *calls)
+	})
+}
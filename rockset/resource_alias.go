@@ -20,9 +20,7 @@ func resourceAlias() *schema.Resource {
 		UpdateContext: resourceAliasUpdate,
 		DeleteContext: resourceAliasDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_alias"), resourceAliasRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -64,6 +62,9 @@ func resourceAlias() *schema.Resource {
 				},
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(defaultWaitTimeout),
+		},
 	}
 }
 
@@ -181,7 +182,10 @@ func resourceAliasDelete(ctx context.Context, d *schema.ResourceData, meta inter
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilAliasGone(ctx, workspace, name)
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "alias "+d.Id()+" to be deleted",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilAliasGone(ctx, workspace, name)
+		}, aliasState(rc, workspace, name))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -10,11 +10,12 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
 func TestAccAlias_Basic(t *testing.T) {
+	useCassette(t)
+
 	var alias openapi.Alias
 
 	name := randomName("alias")
@@ -84,7 +85,7 @@ func TestAccAlias_Basic(t *testing.T) {
 
 // clean up any lingering test alias from a previous run
 func testAccRemoveAlias(t *testing.T, workspace, alias string) {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	err := rc.DeleteAlias(context.TODO(), workspace, alias)
 	if err != nil {
@@ -93,7 +94,7 @@ func testAccRemoveAlias(t *testing.T, workspace, alias string) {
 }
 
 func testAccCheckRocksetAliasDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_alias" {
@@ -131,7 +132,7 @@ func testAccCheckRocksetAliasExists(resource string, alias *openapi.Alias) resou
 		}
 		workspace, name := workspaceAndNameFromID(rs.Primary.ID)
 
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		resp, err := rc.GetAlias(testCtx, workspace, name)
 		if err != nil {
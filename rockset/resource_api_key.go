@@ -21,9 +21,12 @@ func resourceApiKey() *schema.Resource {
 		ReadContext:   resourceApiKeyRead,
 		DeleteContext: resourceApiKeyDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{
+			parts:     []idPart{{attribute: "name"}, {attribute: "user"}},
+			separator: ":",
+			optional:  1,
+			example:   "my-key:user@example.com",
+		}, resourceApiKeyRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
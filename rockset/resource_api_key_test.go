@@ -8,12 +8,13 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/option"
 )
 
 func TestAccApiKey_Basic(t *testing.T) {
+	useCassette(t)
+
 	var apiKey openapi.ApiKey
 	var keyValueOnCreation string
 
@@ -84,7 +85,7 @@ func TestAccApiKey_Basic(t *testing.T) {
 }
 
 func testAccCheckRocksetApiKeyDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_api_key" {
@@ -122,7 +123,7 @@ func testAccCheckRocksetApiKeyDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetApiKeyExists(resource string, apiKey *openapi.ApiKey) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -23,9 +23,10 @@ func resourceAutoScalingPolicy() *schema.Resource {
 		UpdateContext: resourceAutoScalingPolicyUpdate,
 		DeleteContext: resourceAutoScalingPolicyDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{
+			parts:   []idPart{{attribute: "virtual_instance_id"}},
+			example: "29e4a43c-fff4-4fe6-80e3-1ee57bc22e82",
+		}, resourceAutoScalingPolicyRead),
 
 		Schema: map[string]*schema.Schema{
 			"virtual_instance_id": {
@@ -142,7 +143,7 @@ func resourceAutoScalingPolicyRead(ctx context.Context, d *schema.ResourceData,
 
 	vi, err := rc.GetVirtualInstance(ctx, id)
 	if err != nil {
-		return DiagFromErr(err)
+		return checkForNotFoundError(d, err)
 	}
 
 	policy := vi.GetAutoScalingPolicy()
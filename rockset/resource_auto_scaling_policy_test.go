@@ -7,6 +7,8 @@ import (
 )
 
 func TestAccAutoScalingPolicy_Basic(t *testing.T) {
+	useCassette(t)
+
 	vi := "rockset_autoscaling_policy.main"
 
 	resource.Test(t, resource.TestCase{
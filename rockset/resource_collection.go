@@ -2,6 +2,7 @@ package rockset
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
@@ -14,6 +15,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	"github.com/rockset/rockset-go-client/openapi"
+
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
 )
 
 // The base collection schema will be the foundation
@@ -37,9 +40,11 @@ that contains all of the desired data transformations.
 This is referred to as the collection’s ingest transformation or, historically, its field mapping query.
 
 For more information see https://rockset.com/docs/ingest-transformation/`,
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:             schema.TypeString,
+			Optional:         true,
+			DiffSuppressFunc: suppressEquivalentSQL,
 		},
+		"referenced_collections": referencedCollectionsSchema("ingest_transformation"),
 		"name": {
 			Description:  "Unique identifier for the collection. Can contain alphanumeric or dash characters.",
 			Type:         schema.TypeString,
@@ -76,6 +81,7 @@ For more information see https://rockset.com/docs/ingest-transformation/`,
 			ForceNew:     true,
 			RequiredWith: []string{"wait_for_documents"},
 		},
+		"wait_for": waitForSchema(),
 		"wait_for_documents": {
 			Description:  "Wait until the collection has documents. The default is to wait for 0 documents, which means it doesn't wait.",
 			Type:         schema.TypeInt,
@@ -132,6 +138,14 @@ func parseBaseCollection(collection *openapi.Collection, d *schema.ResourceData)
 	return nil // No errors
 }
 
+// ingestTransformationSQL returns the ingest transformation of the collection, so errors in it point at the attribute.
+func ingestTransformationSQL(d *schema.ResourceData) diagnostics.SQL {
+	return diagnostics.SQL{
+		Query: d.Get("ingest_transformation").(string),
+		Path:  []interface{}{"ingest_transformation"},
+	}
+}
+
 func createBaseCollectionRequest(d *schema.ResourceData) *openapi.CreateCollectionRequest {
 	/*
 		Parses resource data and returns a create collection request
@@ -167,7 +181,7 @@ func createBaseCollectionRequest(d *schema.ResourceData) *openapi.CreateCollecti
 const defaultCollectionTimeout = 20 * time.Minute
 
 func resourceCollection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", &schema.Resource{
 		Description: "Manages a basic collection with no sources. Usually used for the write api.",
 
 		CreateContext: resourceCollectionCreate,
@@ -175,15 +189,16 @@ func resourceCollection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate,
 		DeleteContext: resourceCollectionDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceCollectionRead),
+
+		CustomizeDiff: validateWaitFor(false),
 
 		Schema: baseCollectionSchema(),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	}))
 }
 
 func resourceCollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -196,7 +211,7 @@ func resourceCollectionCreate(ctx context.Context, d *schema.ResourceData, meta
 	params := createBaseCollectionRequest(d)
 	_, err := rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	if err = waitForCollectionAndDocuments(ctx, rc, d, workspace, name); err != nil {
@@ -240,7 +255,10 @@ func resourceCollectionDelete(ctx context.Context, d *schema.ResourceData, meta
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilCollectionGone(ctx, workspace, name)
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "collection "+toID(workspace, name)+" to be deleted",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilCollectionGone(ctx, workspace, name)
+		}, collectionState(rc, workspace, name))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -265,7 +283,7 @@ func resourceCollectionUpdate(ctx context.Context, d *schema.ResourceData, meta
 
 	_, err = rc.UpdateCollection(ctx, workspace, name, options...)
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	return diags
@@ -277,7 +295,11 @@ func waitForCollectionAndDocuments(ctx context.Context, rc *rockset.RockClient,
 			"workspace": workspace,
 			"name":      name,
 		})
-		if err := rc.Wait.UntilCollectionReady(ctx, workspace, name); err != nil {
+		err := waitWithTimeout(ctx, d, schema.TimeoutCreate, "collection "+toID(workspace, name)+" to be ready",
+			func(ctx context.Context) error {
+				return rc.Wait.UntilCollectionReady(ctx, workspace, name)
+			}, collectionState(rc, workspace, name))
+		if err != nil {
 			return err
 		}
 	}
@@ -287,7 +309,23 @@ func waitForCollectionAndDocuments(ctx context.Context, rc *rockset.RockClient,
 			"workspace": workspace,
 			"name":      name,
 		})
-		if err := rc.Wait.UntilCollectionHasDocuments(ctx, workspace, name, int64(nDocs)); err != nil {
+		err := waitWithTimeout(ctx, d, schema.TimeoutCreate,
+			fmt.Sprintf("collection %s to have %d documents", toID(workspace, name), nDocs),
+			func(ctx context.Context) error {
+				return rc.Wait.UntilCollectionHasDocuments(ctx, workspace, name, int64(nDocs))
+			}, collectionState(rc, workspace, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	if conditions := readinessConditions(d); len(conditions) > 0 {
+		tflog.Debug(ctx, "waiting for collection readiness conditions", map[string]interface{}{
+			"workspace":  workspace,
+			"name":       name,
+			"conditions": len(conditions),
+		})
+		if err := waitForReadiness(ctx, rc, d, workspace, name, conditions); err != nil {
 			return err
 		}
 	}
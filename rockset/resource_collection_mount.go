@@ -12,6 +12,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
 )
 
 func resourceCollectionMount() *schema.Resource {
@@ -22,9 +24,14 @@ func resourceCollectionMount() *schema.Resource {
 		ReadContext:   resourceCollectionMountRead,
 		DeleteContext: resourceCollectionMountDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{
+			parts: []idPart{
+				{"path", validation.StringMatch(pathRegexp, "must be of the form workspace.collection")},
+				{attribute: "virtual_instance_id"},
+			},
+			separator: ":",
+			example:   "commons.my_collection:29e4a43c-fff4-4fe6-80e3-1ee57bc22e82",
+		}, resourceCollectionMountRead),
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -73,6 +80,10 @@ func resourceCollectionMount() *schema.Resource {
 					"must be of the form workspace.collection"),
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWaitTimeout),
+			Delete: schema.DefaultTimeout(defaultWaitTimeout),
+		},
 	}
 }
 
@@ -102,7 +113,10 @@ func resourceCollectionMountCreate(ctx context.Context, d *schema.ResourceData,
 	collection := fields[1]
 
 	// TODO make it possible to skip waiting, and then parse the fields from the created vi
-	err = rc.Wait.UntilMountActive(ctx, vid, workspace, collection)
+	err = waitWithTimeout(ctx, d, schema.TimeoutCreate, "mount "+id+" to be active",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilMountActive(ctx, vid, workspace, collection)
+		}, mountState(rc, vid, path))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -130,6 +144,17 @@ func resourceCollectionMountRead(ctx context.Context, d *schema.ResourceData, me
 
 	m, err := rc.GetCollectionMount(ctx, vid, path)
 	if err != nil {
+		if client.IsNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+
+		// the API returns a bad request instead of not found when the collection exists, but isn't mounted
+		if mounted, lerr := isMounted(ctx, rc, vid, path); lerr == nil && !mounted {
+			d.SetId("")
+			return diags
+		}
+
 		return DiagFromErr(err)
 	}
 
@@ -140,6 +165,22 @@ func resourceCollectionMountRead(ctx context.Context, d *schema.ResourceData, me
 	return diags
 }
 
+// isMounted returns true if the collection is mounted on the virtual instance.
+func isMounted(ctx context.Context, rc *rockset.RockClient, vID, path string) (bool, error) {
+	mounts, err := rc.ListCollectionMounts(ctx, vID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, m := range mounts {
+		if m.GetCollectionPath() == path {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 func resourceCollectionMountDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	rc := meta.(*rockset.RockClient)
 	var diags diag.Diagnostics
@@ -159,7 +200,10 @@ func resourceCollectionMountDelete(ctx context.Context, d *schema.ResourceData,
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilMountGone(ctx, vid, fields[0], fields[1])
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "mount "+d.Id()+" to be removed",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilMountGone(ctx, vid, fields[0], fields[1])
+		}, mountState(rc, vid, path))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -9,11 +9,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
 func TestAccCollection_Basic(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	values := Values{
@@ -60,6 +61,8 @@ func TestAccCollection_Basic(t *testing.T) {
 }
 
 func TestAccCollection_StorageCompressionType(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	values := Values{
@@ -103,6 +106,8 @@ func TestAccCollection_StorageCompressionType(t *testing.T) {
 }
 
 func TestAccCollection_Timeout(t *testing.T) {
+	useCassette(t)
+
 	values := Values{
 		Name:          randomName("collection"),
 		Description:   description(),
@@ -125,6 +130,8 @@ func TestAccCollection_Timeout(t *testing.T) {
 }
 
 func TestAccCollection_IngestTransformation(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	values := Values{
@@ -175,7 +182,7 @@ func TestAccCollection_IngestTransformation(t *testing.T) {
 Check if any type of collection was successfully destroyed
 */
 func testAccCheckRocksetCollectionDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if !strings.Contains(rs.Type, "_collection") {
@@ -198,7 +205,7 @@ func testAccCheckRocksetCollectionDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetCollectionExists(resource string, collection *openapi.Collection) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -218,7 +225,7 @@ func testAccCheckRocksetCollectionExists(resource string, collection *openapi.Co
 }
 func testAccCheckRocksetCollectionSame(resource string, collection *openapi.Collection) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
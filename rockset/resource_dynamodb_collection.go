@@ -89,7 +89,7 @@ func dynamoDBCollectionSchema() map[string]*schema.Schema {
 } // End func
 
 func resourceDynamoDBCollection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", withSourceSuspension(&schema.Resource{
 		Description: "Manages a collection with an DynamoDB source attached.",
 
 		CreateContext: resourceDynamoDBCollectionCreate,
@@ -97,17 +97,18 @@ func resourceDynamoDBCollection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate, // No change from base collection update
 		DeleteContext: resourceCollectionDelete, // No change from base collection delete
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceDynamoDBCollectionRead),
+
+		CustomizeDiff: validateWaitFor(false),
 
 		// This schema will use the base collection schema as a foundation
 		// And layer on just the necessary fields for a dynamodb collection
 		Schema: mergeSchemas(baseCollectionSchema(), dynamoDBCollectionSchema()),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	})))
 }
 
 func resourceDynamoDBCollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -125,7 +126,7 @@ func resourceDynamoDBCollectionCreate(ctx context.Context, d *schema.ResourceDat
 
 	_, err = rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	if err = waitForCollectionAndDocuments(ctx, rc, d, workspace, name); err != nil {
@@ -146,7 +147,7 @@ func resourceDynamoDBCollectionRead(ctx context.Context, d *schema.ResourceData,
 
 	collection, err := rc.GetCollection(ctx, workspace, name)
 	if err != nil {
-		return DiagFromErr(err)
+		return checkForNotFoundError(d, err)
 	}
 
 	// Gets all the fields any generic collection has
@@ -190,6 +191,10 @@ func parseDynamoDBCollection(collection *openapi.Collection, d *schema.ResourceD
 func flattenSourceParams(sources *[]openapi.Source) []interface{} {
 	convertedList := make([]interface{}, 0, len(*sources))
 	for _, source := range *sources {
+		if source.Dynamodb == nil {
+			continue
+		}
+
 		m := make(map[string]interface{})
 		m["integration_name"] = source.IntegrationName
 		m["table_name"] = source.Dynamodb.TableName
@@ -202,6 +207,7 @@ func flattenSourceParams(sources *[]openapi.Source) []interface{} {
 		m["state"] = source.Dynamodb.Status.GetState()
 		m["stream_last_processed_at"] = source.Dynamodb.Status.GetStreamLastProcessedAt()
 		m["use_scan_api"] = source.Dynamodb.UseScanApi
+		flattenSourceSuspension(m, source)
 
 		convertedList = append(convertedList, m)
 	}
@@ -1,16 +1,20 @@
 package rockset
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestAccDynamoDBCollection_Basic(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 	rcu := 5
 	values := Values{
@@ -41,11 +45,20 @@ func TestAccDynamoDBCollection_Basic(t *testing.T) {
 				),
 				ExpectNonEmptyPlan: false,
 			},
+			testAccDeletedStep(t, getHCLTemplate("dynamodb_collection.tf", values),
+				func(ctx context.Context, rc *rockset.RockClient) error {
+					if err := rc.DeleteCollection(ctx, values.Workspace, values.Collection); err != nil {
+						return err
+					}
+					return rc.Wait.UntilCollectionGone(ctx, values.Workspace, values.Collection)
+				}),
 		},
 	})
 }
 
 func TestAccDynamoDBCollection_ScanTrue(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 	useScanApi := true
 	rcu := 5
@@ -83,6 +96,8 @@ func TestAccDynamoDBCollection_ScanTrue(t *testing.T) {
 }
 
 func TestAccDynamoDBCollection_ScanFalse(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 	useScanApi := false
 
@@ -119,6 +134,8 @@ func TestAccDynamoDBCollection_ScanFalse(t *testing.T) {
 }
 
 func TestAccDynamoDBCollection_ScanNull(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 	rcu := 5
 
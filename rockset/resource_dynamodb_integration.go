@@ -18,9 +18,7 @@ func resourceDynamoDBIntegration() *schema.Resource {
 		ReadContext:   resourceDynamoDBIntegrationRead,
 		DeleteContext: resourceIntegrationDelete, // common among <type>integrations
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("my-dynamodb-integration"), resourceDynamoDBIntegrationRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -5,11 +5,12 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
 func TestAccDynamoDBIntegration_Basic(t *testing.T) {
+	useCassette(t)
+
 	var dynamoDBIntegration openapi.DynamodbIntegration
 
 	values := Values{
@@ -42,7 +43,7 @@ func TestAccDynamoDBIntegration_Basic(t *testing.T) {
 
 func testAccCheckRocksetDynamoDBIntegrationExists(resource string, dynamoDBIntegration *openapi.DynamodbIntegration) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -10,7 +10,7 @@ import (
 )
 
 func resourceGCSCollection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", withSourceSuspension(&schema.Resource{
 		Description: "Manages a collection with an GCS source attached.",
 
 		CreateContext: resourceGCSCollectionCreate,
@@ -18,17 +18,18 @@ func resourceGCSCollection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate, // No change from base collection update
 		DeleteContext: resourceCollectionDelete, // No change from base collection delete
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceGCSCollectionRead),
+
+		CustomizeDiff: validateWaitFor(false),
 
 		// This schema will use the base collection schema as a foundation
 		// And layer on just the necessary fields for an gcs collection
 		Schema: mergeSchemas(baseCollectionSchema(), gcsCollectionSchema()),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	})))
 }
 
 func gcsCollectionSchema() map[string]*schema.Schema {
@@ -89,7 +90,7 @@ func resourceGCSCollectionCreate(ctx context.Context, d *schema.ResourceData, me
 
 	_, err = rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	if err = waitForCollectionAndDocuments(ctx, rc, d, workspace, name); err != nil {
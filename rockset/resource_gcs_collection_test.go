@@ -15,6 +15,8 @@ const testGCSCollectionWorkspace = "commons"
 const testGCSCollectionDescription = "Terraform provider acceptance tests."
 
 func TestAccGCSCollection_Basic(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	resource.Test(t, resource.TestCase{
@@ -18,9 +18,7 @@ func resourceGCSIntegration() *schema.Resource {
 		ReadContext:   resourceGCSIntegrationRead,
 		DeleteContext: resourceIntegrationDelete, // common among <type>integrations
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("my-gcs-integration"), resourceGCSIntegrationRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -5,7 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
@@ -13,6 +12,8 @@ const testGCSIntegrationName = "terraform-provider-acceptance-test-gcs-integrati
 const testGCSIntegrationDescription = "Terraform provider acceptance tests."
 
 func TestAccGCSIntegration_Basic(t *testing.T) {
+	useCassette(t)
+
 	var gcsIntegration openapi.GcsIntegration
 
 	resource.Test(t, resource.TestCase{
@@ -38,7 +39,7 @@ func TestAccGCSIntegration_Basic(t *testing.T) {
 func testAccCheckRocksetGCSIntegrationExists(resource string,
 	gcsIntegration *openapi.GcsIntegration) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -111,7 +111,7 @@ func kafkaCollectionSchema() map[string]*schema.Schema {
 } // End func
 
 func resourceKafkaCollection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", withSourceSuspension(&schema.Resource{
 		Description: "Manages a collection created from a Kafka source. " +
 			"The `use_v3` field must match the integration which the collection is created from.",
 
@@ -120,22 +120,19 @@ func resourceKafkaCollection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate, // No change from base collection update
 		DeleteContext: resourceCollectionDelete, // No change from base collection delete
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceKafkaCollectionRead),
 
-		CustomizeDiff: func(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
-			// TODO use_v3=true validation
-			return nil
-		},
+		// TODO use_v3=true validation
+		CustomizeDiff: validateWaitFor(true),
 
 		// This schema will use the base collection schema as a foundation
 		// And layer on just the necessary fields for a Kafka collection
 		Schema: mergeSchemas(baseCollectionSchema(), kafkaCollectionSchema()),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	})))
 }
 
 func resourceKafkaCollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -158,7 +155,7 @@ func resourceKafkaCollectionCreate(ctx context.Context, d *schema.ResourceData,
 
 	_, err = rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	if err = waitForCollectionAndDocuments(ctx, rc, d, workspace, name); err != nil {
@@ -219,12 +216,17 @@ func parseKafkaCollection(collection *openapi.Collection, d *schema.ResourceData
 func flattenKafkaSourceParams(sources *[]openapi.Source) []interface{} {
 	convertedList := make([]interface{}, 0, len(*sources))
 	for _, source := range *sources {
+		if source.Kafka == nil {
+			continue
+		}
+
 		m := make(map[string]interface{})
 		m["integration_name"] = source.IntegrationName
 		m["topic_name"] = source.Kafka.KafkaTopicName
 		m["consumer_group_id"] = source.Kafka.ConsumerGroupId
 		m["offset_reset_policy"] = source.Kafka.OffsetResetPolicy
 		m["use_v3"] = source.Kafka.UseV3
+		flattenSourceSuspension(m, source)
 
 		m["status"] = flattenKafkaSourceStatus(source.Kafka.Status)
 		convertedList = append(convertedList, m)
@@ -248,8 +250,8 @@ func flattenKafkaSourceStatus(status *openapi.StatusKafka) []interface{} {
 		m["documents_processed"] = v
 	}
 
-	if len(status.KafkaPartitions) > 0 {
-		m["partitions"] = flattenKafkaSourcePartitions(status.KafkaPartitions)
+	if partitions := status.GetKafkaPartitions(); len(partitions) > 0 {
+		m["partitions"] = flattenKafkaSourcePartitions(partitions)
 	}
 
 	return []interface{}{m}
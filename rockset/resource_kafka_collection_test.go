@@ -11,6 +11,8 @@ import (
 )
 
 func TestAccKafkaCollection_BasicV2(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	t.Skip("requires special setup")
@@ -43,6 +45,8 @@ func TestAccKafkaCollection_BasicV2(t *testing.T) {
 }
 
 func TestAccKafkaCollection_BasicV3(t *testing.T) {
+	useCassette(t)
+
 	t.Skip("kafka needs to be reconfigured")
 	var collection openapi.Collection
 
@@ -22,9 +22,7 @@ func resourceKafkaIntegration() *schema.Resource {
 		ReadContext:   resourceKafkaIntegrationRead,
 		DeleteContext: resourceIntegrationDelete, // common among <type>integrations
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("my-kafka-integration"), resourceKafkaIntegrationRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -116,6 +114,9 @@ func resourceKafkaIntegration() *schema.Resource {
 				ForceNew:    true,
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWaitTimeout),
+		},
 	}
 }
 
@@ -209,7 +210,11 @@ func resourceKafkaIntegrationCreate(ctx context.Context, d *schema.ResourceData,
 			tflog.Debug(ctx, "waiting for integration", map[string]interface{}{
 				"name": name,
 			})
-			if err = rc.Wait.UntilKafkaIntegrationActive(ctx, name); err != nil {
+			err = waitWithTimeout(ctx, d, schema.TimeoutCreate, "integration "+name+" to be active",
+				func(ctx context.Context) error {
+					return rc.Wait.UntilKafkaIntegrationActive(ctx, name)
+				}, kafkaIntegrationState(rc, name))
+			if err != nil {
 				return DiagFromErr(err)
 			}
 		}
@@ -8,7 +8,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
@@ -16,6 +15,8 @@ const testKafkaIntegrationName = "terraform-provider-acceptance-test-kafka-integ
 const testKafkaIntegrationDescription = "Terraform provider acceptance tests."
 
 func TestAccKafkaIntegration_BasicV2(t *testing.T) {
+	useCassette(t)
+
 	var kafkaIntegration openapi.KafkaIntegration
 
 	t.Skip("requires special setup")
@@ -51,6 +52,8 @@ func TestAccKafkaIntegration_BasicV2(t *testing.T) {
 }
 
 func TestAccKafkaIntegration_BasicV3(t *testing.T) {
+	useCassette(t)
+
 	var kafkaIntegration openapi.KafkaIntegration
 
 	t.Skip("test broken due to API change")
@@ -87,7 +90,7 @@ func TestAccKafkaIntegration_BasicV3(t *testing.T) {
 
 func testAccCheckRocksetKafkaIntegrationExists(resource string, kafkaIntegration *openapi.KafkaIntegration) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -71,7 +71,7 @@ func kinesisCollectionSchema() map[string]*schema.Schema {
 } // End func
 
 func resourceKinesisCollection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", withSourceSuspension(&schema.Resource{
 		Description: "Manages a collection with an Kinesis source attached.",
 
 		CreateContext: resourceKinesisCollectionCreate,
@@ -79,17 +79,18 @@ func resourceKinesisCollection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate, // No change from base collection update
 		DeleteContext: resourceCollectionDelete, // No change from base collection delete
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceKinesisCollectionRead),
+
+		CustomizeDiff: validateWaitFor(false),
 
 		// This schema will use the base collection schema as a foundation
 		// And layer on just the necessary fields for a Kinesis collection
 		Schema: mergeSchemas(baseCollectionSchema(), kinesisCollectionSchema()),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	})))
 }
 
 func resourceKinesisCollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -107,7 +108,7 @@ func resourceKinesisCollectionCreate(ctx context.Context, d *schema.ResourceData
 
 	_, err = rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	if err = waitForCollectionAndDocuments(ctx, rc, d, workspace, name); err != nil {
@@ -193,6 +194,7 @@ func flattenKinesisSourceParams(sources *[]openapi.Source) []interface{} {
 		}
 
 		m["dms_primary_key"] = source.Kinesis.GetDmsPrimaryKey()
+		flattenSourceSuspension(m, source)
 
 		convertedList = append(convertedList, m)
 	}
@@ -14,6 +14,8 @@ const testKinesisCollectionWorkspace = "commons"
 const testKinesisCollectionDescription = "Terraform provider acceptance tests."
 
 func TestAccKinesisCollection_Basic(t *testing.T) {
+	useCassette(t)
+
 	t.Skip("kinesis needs to be reconfigured")
 	var collection openapi.Collection
 
@@ -18,9 +18,7 @@ func resourceKinesisIntegration() *schema.Resource {
 		ReadContext:   resourceKinesisIntegrationRead,
 		DeleteContext: resourceIntegrationDelete, // common among <type>integrations
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("my-kinesis-integration"), resourceKinesisIntegrationRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -5,7 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
@@ -14,6 +13,8 @@ const testKinesisIntegrationDescription = "Terraform provider acceptance tests."
 const testKinesisIntegrationRoleArn = "arn:aws:iam::469279130686:role/terraform-provider-rockset-tests-kinesis"
 
 func TestAccKinesisIntegration_Basic(t *testing.T) {
+	useCassette(t)
+
 	var kinesisIntegration openapi.KinesisIntegration
 
 	resource.Test(t, resource.TestCase{
@@ -41,7 +42,7 @@ func TestAccKinesisIntegration_Basic(t *testing.T) {
 
 func testAccCheckRocksetKinesisIntegrationExists(resource string, kinesisIntegration *openapi.KinesisIntegration) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -109,7 +109,7 @@ Selecting this option will increase load on your upstream MongoDB database.`,
 } // End func
 
 func resourceMongoDBCollection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", withSourceSuspension(&schema.Resource{
 		Description: "Manages a collection with an MongoDB source attached.",
 
 		CreateContext: resourceMongoDBCollectionCreate,
@@ -117,17 +117,18 @@ func resourceMongoDBCollection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate, // No change from base collection update
 		DeleteContext: resourceCollectionDelete, // No change from base collection delete
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceMongoDBCollectionRead),
+
+		CustomizeDiff: validateWaitFor(false),
 
 		// This schema will use the base collection schema as a foundation
 		// And layer on just the necessary fields for a MongoDB collection
 		Schema: mergeSchemas(baseCollectionSchema(), mongoDBCollectionSchema()),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	})))
 }
 
 func resourceMongoDBCollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -145,7 +146,7 @@ func resourceMongoDBCollectionCreate(ctx context.Context, d *schema.ResourceData
 
 	_, err = rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 
 	if err = waitForCollectionAndDocuments(ctx, rc, d, workspace, name); err != nil {
@@ -225,6 +226,7 @@ func flattenMongoDBSourceParams(sources *[]openapi.Source) []interface{} {
 		m["stream_records_inserted"] = source.Mongodb.Status.GetStreamRecordsInserted()
 		m["stream_records_updated"] = source.Mongodb.Status.GetStreamRecordsUpdated()
 		m["stream_records_deleted"] = source.Mongodb.Status.GetStreamRecordsDeleted()
+		flattenSourceSuspension(m, source)
 
 		convertedList = append(convertedList, m)
 	}
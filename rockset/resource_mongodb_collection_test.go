@@ -15,6 +15,8 @@ const testMongoDBCollectionWorkspace = "commons"
 const testMongoDBCollectionDescription = "Terraform provider acceptance tests."
 
 func TestAccMongoDBCollection_Basic(t *testing.T) {
+	useCassette(t)
+
 	t.Skip("mongo account needs to be reconfigured")
 	var collection openapi.Collection
 
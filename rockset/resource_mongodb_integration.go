@@ -18,9 +18,7 @@ func resourceMongoDBIntegration() *schema.Resource {
 		ReadContext:   resourceMongoDBIntegrationRead,
 		DeleteContext: resourceIntegrationDelete, // common among <type>integrations
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("my-mongodb-integration"), resourceMongoDBIntegrationRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -5,7 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
@@ -13,6 +12,8 @@ const testMongoDBIntegrationName = "terraform-provider-acceptance-test-mongodb-i
 const testMongoDBIntegrationDescription = "Terraform provider acceptance tests."
 
 func TestAccMongoDBIntegration_Basic(t *testing.T) {
+	useCassette(t)
+
 	t.Skip("mongodb needs to be reconfigured")
 	var mongoDBIntegration openapi.MongoDbIntegration
 
@@ -39,7 +40,7 @@ func TestAccMongoDBIntegration_Basic(t *testing.T) {
 
 func testAccCheckRocksetMongoDBIntegrationExists(resource string, mongoDBIntegration *openapi.MongoDbIntegration) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -3,6 +3,7 @@ package rockset
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -10,9 +11,46 @@ import (
 	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/option"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+	"github.com/rockset/terraform-provider-rockset/internal/sqlscan"
 )
 
 func resourceQueryLambda() *schema.Resource { //nolint:funlen
+	sqlElem := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"query": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentSQL,
+			},
+			"default_parameter": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Description: "Type of the parameter, one of bool, date, datetime, float, int, string, " +
+								"time or timestamp.",
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"value": {
+							Description: "Value of the parameter, which must be of its type.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+
 	return &schema.Resource{
 		Description: "Manages a Rockset Query Lambda.",
 
@@ -23,9 +61,7 @@ func resourceQueryLambda() *schema.Resource { //nolint:funlen
 
 		CustomizeDiff: resourceQueryLambdaDiff,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_query_lambda"), resourceQueryLambdaRead),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -60,46 +96,60 @@ func resourceQueryLambda() *schema.Resource { //nolint:funlen
 			"sql": {
 				Type:     schema.TypeSet,
 				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"query": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"default_parameter": {
-							Type:     schema.TypeSet,
-							Optional: true,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"name": {
-										Type:     schema.TypeString,
-										Required: true,
-									},
-									"type": {
-										Type:     schema.TypeString,
-										Required: true,
-									},
-									"value": {
-										Type:     schema.TypeString,
-										Required: true,
-									},
-								},
-							},
-						},
-					},
-				},
+				Elem:     sqlElem,
+				Set:      hashQueryLambdaSQL(sqlElem),
 			},
+			"referenced_collections": referencedCollectionsSchema("sql.query"),
 		},
 	}
 }
 
+// hashQueryLambdaSQL hashes the sql block with its query normalized, as the block is an element of a set, which
+// otherwise would be replaced when only the formatting of the query changes.
+func hashQueryLambdaSQL(elem *schema.Resource) schema.SchemaSetFunc {
+	hash := schema.HashResource(elem)
+
+	return func(v interface{}) int {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return hash(v)
+		}
+
+		normalized := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			normalized[k] = val
+		}
+		if query, ok := m["query"].(string); ok {
+			normalized["query"] = sqlscan.Normalize(query)
+		}
+
+		return hash(normalized)
+	}
+}
+
 // resourceQueryLambdaDiff checks if anything in the sql has changed, and if so, it'll
-// signal that the computer version will change
+// signal that the computer version will change. Changing only the formatting of the query doesn't publish a new version.
 func resourceQueryLambdaDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
-	if diff.HasChange("sql") {
-		return diff.SetNewComputed("version")
+	if !diff.HasChange("sql") {
+		return nil
+	}
+
+	o, n := diff.GetChange("sql")
+	if diff.NewValueKnown("sql") && queryLambdaSQLEquivalent(makeQueryLambdaSQL(o), makeQueryLambdaSQL(n)) {
+		return nil
 	}
-	return nil
+
+	return diff.SetNewComputed("version")
+}
+
+// queryLambdaSQLEquivalent checks if the queries only differ in formatting, and the default parameters are the same.
+func queryLambdaSQLEquivalent(a, b openapi.QueryLambdaSql) bool {
+	if !sqlscan.Equivalent(a.Query, b.Query) || len(a.DefaultParameters) != len(b.DefaultParameters) {
+		return false
+	}
+
+	// the parameters are listed in the order of their hashes, so equal sets list them in the same order
+	return len(a.DefaultParameters) == 0 || reflect.DeepEqual(a.DefaultParameters, b.DefaultParameters)
 }
 
 type qlFn func(context.Context, string, string, string, ...option.CreateQueryLambdaOption) (openapi.QueryLambdaVersion,
@@ -116,7 +166,7 @@ func resourceQueryLambdaCreateOrUpdate(fn qlFn) schema.CreateContextFunc {
 
 		ql, err := fn(ctx, workspace, name, sql.Query, options...)
 		if err != nil {
-			return DiagFromErr(err)
+			return DiagFromErr(err, diagnostics.SQL{Query: sql.Query, Path: []interface{}{"sql", 0, "query"}})
 		}
 
 		if ql.Version != nil {
@@ -259,7 +309,7 @@ func getQueryLambda(ctx context.Context, rc *rockset.RockClient, workspace strin
 		}
 	}
 
-	return nil, fmt.Errorf("query lambda %s not found in workspace %s", name, workspace)
+	return nil, fmt.Errorf("query lambda %s in workspace %s %w", name, workspace, client.ErrNotFound)
 }
 
 func makeDefaultParameters(input interface{}) []openapi.QueryParameter {
@@ -287,10 +337,10 @@ func makeDefaultParameters(input interface{}) []openapi.QueryParameter {
 
 func flattenQueryLambdaSQL(sql *openapi.QueryLambdaSql) []interface{} {
 	var m = make(map[string]interface{})
-	m["query"] = sql.Query
+	m["query"] = sql.GetQuery()
 
 	var r []interface{}
-	for _, qp := range sql.DefaultParameters {
+	for _, qp := range sql.GetDefaultParameters() {
 		m := make(map[string]interface{})
 		m["name"] = qp.Name
 		m["type"] = qp.Type
@@ -21,9 +21,15 @@ func resourceQueryLambdaTag() *schema.Resource {
 		DeleteContext: resourceQueryLambdaTagDelete,
 		UpdateContext: resourceQueryLambdaTagCreate,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{
+			parts: []idPart{
+				{"workspace", rocksetNameValidator},
+				{"query_lambda", rocksetNameValidator},
+				{"name", rocksetNameValidator},
+			},
+			separator: ".",
+			example:   "commons.my_query_lambda.latest",
+		}, resourceQueryLambdaTagRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
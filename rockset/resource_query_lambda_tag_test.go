@@ -6,11 +6,12 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
 func TestAccQueryLambdaTag_Basic(t *testing.T) {
+	useCassette(t)
+
 	var queryLambdaTag1, queryLambdaTag2 openapi.QueryLambdaTag
 
 	v1 := Values{
@@ -64,7 +65,7 @@ func TestAccQueryLambdaTag_Basic(t *testing.T) {
 }
 
 func testAccCheckRocksetQueryLambdaTagDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_query_lambda_tag" {
@@ -87,7 +88,7 @@ func testAccCheckRocksetQueryLambdaTagDestroy(s *terraform.State) error {
 func testAccCheckRocksetQueryLambdaTagExists(resource string,
 	queryLambdaTag *openapi.QueryLambdaTag) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -1,17 +1,21 @@
 package rockset
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccQueryLambda_Basic(t *testing.T) {
+	useCassette(t)
+
 	var queryLambda openapi.QueryLambda
 
 	sql := "SELECT * FROM commons._events WHERE _events._event_time > :start AND _events._event_time < :end "
@@ -61,6 +65,8 @@ func TestAccQueryLambda_Basic(t *testing.T) {
 }
 
 func TestAccQueryLambda_NoDefaults(t *testing.T) {
+	useCassette(t)
+
 	var queryLambda openapi.QueryLambda
 
 	v1 := Values{
@@ -92,6 +98,8 @@ func TestAccQueryLambda_NoDefaults(t *testing.T) {
 }
 
 func TestAccQueryLambda_Recreate(t *testing.T) {
+	useCassette(t)
+
 	var queryLambda openapi.QueryLambda
 
 	v1 := Values{
@@ -124,7 +132,7 @@ func TestAccQueryLambda_Recreate(t *testing.T) {
 }
 
 func testAccCheckRocksetQueryLambdaDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_query_lambda" {
@@ -146,7 +154,7 @@ func testAccCheckRocksetQueryLambdaDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetQueryLambdaExists(resource string, queryLambda *openapi.QueryLambda) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -175,3 +183,77 @@ func testAccCheckSql(t *testing.T, queryLambda *openapi.QueryLambda, expectedSql
 		return nil
 	}
 }
+
+func TestHashQueryLambdaSQL(t *testing.T) {
+	elem := resourceQueryLambda().Schema["sql"].Elem.(*schema.Resource)
+	hash := hashQueryLambdaSQL(elem)
+	sql := func(query string) map[string]interface{} {
+		param := elem.Schema["default_parameter"]
+		return map[string]interface{}{
+			"query": query,
+			"default_parameter": schema.NewSet(schema.HashResource(param.Elem.(*schema.Resource)), []interface{}{
+				map[string]interface{}{"name": "limit", "type": "int", "value": "10"},
+			}),
+		}
+	}
+
+	assert.Equal(t, hash(sql("SELECT * FROM commons._events LIMIT :limit")),
+		hash(sql("select *\nfrom commons._events\nlimit :limit")))
+	assert.NotEqual(t, hash(sql("SELECT * FROM commons._events LIMIT :limit")),
+		hash(sql("SELECT * FROM commons._events LIMIT 1")))
+}
+
+func TestResourceQueryLambdaDiff(t *testing.T) {
+	r := resourceQueryLambda()
+	hash := r.Schema["sql"].Set
+	query := "SELECT * FROM commons._events LIMIT 1"
+
+	state := &terraform.InstanceState{
+		ID: "commons.events",
+		Attributes: map[string]string{
+			"id":          "commons.events",
+			"workspace":   "commons",
+			"name":        "events",
+			"description": "created by Rockset terraform provider",
+			"version":     "v1",
+			"state":       "ACTIVE",
+			"sql.#":       "1",
+
+			"referenced_collections.#": "1",
+			"referenced_collections.0": "commons._events",
+		},
+	}
+	h := fmt.Sprint(hash(map[string]interface{}{
+		"query":             query,
+		"default_parameter": schema.NewSet(schema.HashString, nil),
+	}))
+	state.Attributes["sql."+h+".query"] = query
+	state.Attributes["sql."+h+".default_parameter.#"] = "0"
+
+	config := func(query string, params ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace": "commons",
+			"name":      "events",
+			"sql": []interface{}{
+				map[string]interface{}{"query": query, "default_parameter": params},
+			},
+		})
+	}
+
+	// only the formatting changes, so no new version is published
+	diff, err := r.Diff(context.TODO(), state, config("select *\n  from commons._events\n  limit 1;"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, diff)
+
+	diff, err = r.Diff(context.TODO(), state, config("SELECT * FROM commons._events LIMIT 2"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.True(t, diff.Attributes["version"].NewComputed)
+
+	diff, err = r.Diff(context.TODO(), state, config(query, map[string]interface{}{
+		"name": "limit", "type": "int", "value": "10",
+	}), nil)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	assert.True(t, diff.Attributes["version"].NewComputed)
+}
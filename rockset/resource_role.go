@@ -24,9 +24,7 @@ func resourceRole() *schema.Resource {
 		UpdateContext: resourceRoleUpdate,
 		DeleteContext: resourceRoleDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{parts: []idPart{{attribute: "name"}}, example: "read-only"}, resourceRoleRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -10,7 +10,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
@@ -18,6 +17,8 @@ const testRoleName = "terraform-provider-acceptance-tests"
 const testRoleDescription = "Terraform provider acceptance tests"
 
 func TestAccRole_Basic(t *testing.T) {
+	useCassette(t)
+
 	var role openapi.Role
 
 	resource.Test(t, resource.TestCase{
@@ -124,7 +125,7 @@ func comp(b *strings.Builder, title string, list1, list2 []openapi.Privilege) {
 }
 
 func testAccCheckRocksetRoleDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_role" {
@@ -145,7 +146,7 @@ func testAccCheckRocksetRoleDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetRoleExists(resource string, role *openapi.Role) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -60,7 +60,7 @@ func s3CollectionSchema() map[string]*schema.Schema {
 } // End func
 
 func resourceS3Collection() *schema.Resource {
-	return &schema.Resource{
+	return withReplacementWarning(withDeletionProtection("collection", withSourceSuspension(&schema.Resource{
 		Description: "Manages a collection with on or more S3 sources attached. " +
 			"Uses an S3 integration to access the S3 bucket. If no integration is provided, " +
 			"only data in public buckets are accessible.\n\n",
@@ -70,17 +70,18 @@ func resourceS3Collection() *schema.Resource {
 		UpdateContext: resourceCollectionUpdate, // No change from base collection update
 		DeleteContext: resourceCollectionDelete, // No change from base collection delete
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_collection"), resourceS3CollectionRead),
+
+		CustomizeDiff: validateWaitFor(false),
 
 		// This schema will use the base collection schema as a foundation
 		// And layer on just the necessary fields for an s3 collection
 		Schema: mergeSchemas(baseCollectionSchema(), s3CollectionSchema()),
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(defaultCollectionTimeout),
+			Delete: schema.DefaultTimeout(defaultCollectionTimeout),
 		},
-	}
+	})))
 }
 
 func resourceS3CollectionCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -102,7 +103,7 @@ func resourceS3CollectionCreate(ctx context.Context, d *schema.ResourceData, met
 
 	c, err := rc.CreateCollection(ctx, workspace, name, option.WithCollectionRequest(*params))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, ingestTransformationSQL(d))
 	}
 	tflog.Trace(ctx, "created Rockset collection", map[string]interface{}{"workspace": workspace, "name": name},
 		sourcesToTraceInfo(c.Sources))
@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 	"github.com/rockset/rockset-go-client/wait"
 	"github.com/stretchr/testify/assert"
@@ -13,6 +12,8 @@ import (
 )
 
 func TestAccS3Collection_Basic(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	name := randomName("s3")
@@ -43,6 +44,8 @@ func TestAccS3Collection_Basic(t *testing.T) {
 }
 
 func TestAccS3Collection_Json(t *testing.T) {
+	useCassette(t)
+
 	var collection openapi.Collection
 
 	name := randomName("s3-json")
@@ -88,7 +91,7 @@ func TestAccS3Collection_Json(t *testing.T) {
 
 func triggerWriteAPISourceAdd(t *testing.T, workspace, collection string) {
 	ctx := context.Background()
-	rs := testAccProvider.Meta().(*rockset.RockClient)
+	rs := testAccClient()
 
 	doc := map[string]interface{}{"foo": "bar"}
 
@@ -18,9 +18,7 @@ func resourceS3Integration() *schema.Resource {
 		ReadContext:   resourceS3IntegrationRead,
 		DeleteContext: resourceIntegrationDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("my-s3-integration"), resourceS3IntegrationRead),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
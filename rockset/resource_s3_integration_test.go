@@ -5,11 +5,12 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
 func TestAccS3Integration_Basic(t *testing.T) {
+	useCassette(t)
+
 	var s3Integration openapi.S3Integration
 
 	name := randomName("s3")
@@ -42,7 +43,7 @@ func TestAccS3Integration_Basic(t *testing.T) {
 func testAccCheckRocksetS3IntegrationExists(resource string,
 	s3Integration *openapi.S3Integration) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
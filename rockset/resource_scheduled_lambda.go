@@ -21,9 +21,11 @@ func resourceScheduledLambda() *schema.Resource {
 		UpdateContext: resourceScheduledLambdaUpdate,
 		DeleteContext: resourceScheduledLambdaDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{
+			parts:     []idPart{{"workspace", rocksetNameValidator}, {attribute: "rrn"}},
+			separator: ".",
+			example:   "commons.rrn:sl:usw2a1:4a2c8f7e-5b1d-4c3e-9f6a-8d7b2e1c0a93",
+		}, resourceScheduledLambdaRead),
 
 		Schema: map[string]*schema.Schema{
 			"rrn": {
@@ -100,6 +102,10 @@ func resourceScheduledLambda() *schema.Resource {
 				Optional:    true,
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWaitTimeout),
+			Delete: schema.DefaultTimeout(defaultWaitTimeout),
+		},
 	}
 }
 
@@ -122,7 +128,10 @@ func resourceScheduledLambdaCreate(ctx context.Context, d *schema.ResourceData,
 	scheduledLambdaRrn := *scheduledLambda.Rrn
 	d.SetId(toID(workspace, scheduledLambdaRrn))
 
-	err = rc.Wait.UntilScheduledLambdaAvailable(ctx, workspace, scheduledLambdaRrn)
+	err = waitWithTimeout(ctx, d, schema.TimeoutCreate, "scheduled lambda "+scheduledLambdaRrn+" to be available",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilScheduledLambdaAvailable(ctx, workspace, scheduledLambdaRrn)
+		}, scheduledLambdaState(rc, workspace, scheduledLambdaRrn))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -157,7 +166,7 @@ func resourceScheduledLambdaRead(ctx context.Context, d *schema.ResourceData, me
 
 	scheduledLambda, err := rc.GetScheduledLambda(ctx, workspace, scheduledLambdaRRN)
 	if err != nil {
-		return DiagFromErr(err)
+		return checkForNotFoundError(d, err)
 	}
 
 	if err = parseScheduledLambdaFields(scheduledLambda, d); err != nil {
@@ -178,7 +187,10 @@ func resourceScheduledLambdaDelete(ctx context.Context, d *schema.ResourceData,
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilScheduledLambdaGone(ctx, workspace, scheduledLambdaRRN)
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "scheduled lambda "+scheduledLambdaRRN+" to be deleted",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilScheduledLambdaGone(ctx, workspace, scheduledLambdaRRN)
+		}, scheduledLambdaState(rc, workspace, scheduledLambdaRRN))
 	if err != nil {
 		return DiagFromErr(err)
 	}
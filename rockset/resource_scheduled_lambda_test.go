@@ -1,6 +1,7 @@
 package rockset
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
@@ -12,6 +13,8 @@ import (
 )
 
 func TestAccScheduledLambda_Basic(t *testing.T) {
+	useCassette(t)
+
 	scheduledLambda := "rockset_scheduled_lambda.test_scheduled_lambda"
 
 	type cfg struct {
@@ -23,6 +26,7 @@ func TestAccScheduledLambda_Basic(t *testing.T) {
 	s1 := cfg{"0 0 0 ? * * *", 1, qlName}
 	s2 := cfg{"0 0 0 ? * * *", 2, qlName}
 	s3 := cfg{"0 0 * ? * * *", 3, qlName}
+	var id string
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -63,14 +67,23 @@ func TestAccScheduledLambda_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(scheduledLambda, "query_lambda_name", qlName),
 					resource.TestCheckResourceAttr(scheduledLambda, "tag", "latest"),
 					resource.TestCheckResourceAttr(scheduledLambda, "total_times_to_execute", strconv.FormatInt(s3.TotalTimesToExecute, 10)),
+					testAccStoreID(scheduledLambda, &id),
 				),
 			},
+			testAccDeletedStep(t, getHCLTemplate("scheduled_lambda_basic.tf", s3),
+				func(ctx context.Context, rc *rockset.RockClient) error {
+					ws, rrn := workspaceAndNameFromID(id)
+					if err := rc.DeleteScheduledLambda(ctx, ws, rrn); err != nil {
+						return err
+					}
+					return rc.Wait.UntilScheduledLambdaGone(ctx, ws, rrn)
+				}),
 		},
 	})
 }
 
 func testAccCheckRocksetScheduledLambdaDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_scheduled_lambda" {
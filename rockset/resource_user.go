@@ -21,9 +21,7 @@ i.e. when the state is ACCEPTED.`,
 		DeleteContext: resourceUserDelete,
 		UpdateContext: resourceUserUpdate,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{parts: []idPart{{attribute: "email"}}, example: "user@example.com"}, resourceUserRead),
 
 		Schema: map[string]*schema.Schema{
 			"created_at": {
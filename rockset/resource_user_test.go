@@ -12,6 +12,8 @@ import (
 )
 
 func TestAccUser_Basic(t *testing.T) {
+	useCassette(t)
+
 	var user openapi.User
 
 	// Rockset converts all emails to lowercase
@@ -58,7 +60,7 @@ func TestAccUser_Basic(t *testing.T) {
 }
 
 func testAccCheckRocksetUserDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_user" {
@@ -80,7 +82,7 @@ func testAccCheckRocksetUserDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetUserExists(resource string, user *openapi.User) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -8,6 +8,8 @@ import (
 
 	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/option"
+
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
 )
 
 func resourceView() *schema.Resource {
@@ -16,16 +18,16 @@ func resourceView() *schema.Resource {
 			"~> Note that terraform doesn't understand the contents of the `query` field, " +
 			"so you either have to add a `depends_on` field referencing the `rockset_collection`, " +
 			"or use [`templatefile()`](https://developer.hashicorp.com/terraform/language/functions/templatefile) and pass in a reference to the collection, " +
-			"to get a correct dependency graph",
+			"to get a correct dependency graph. The plan warns about collections, aliases and views which the " +
+			"query references, but which neither exist nor are in the configuration, " +
+			"and `referenced_collections` lists everything the query references.",
 
 		CreateContext: resourceViewCreate,
 		ReadContext:   resourceViewRead,
 		DeleteContext: resourceViewDelete,
 		UpdateContext: resourceViewUpdate,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(workspaceNameIDFormat("commons.my_view"), resourceViewRead),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -43,10 +45,12 @@ func resourceView() *schema.Resource {
 				ValidateFunc: rocksetNameValidator,
 			},
 			"query": {
-				Description: "SQL query used for thw view.",
-				Type:        schema.TypeString,
-				Required:    true,
+				Description:      "SQL query used for thw view.",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentSQL,
 			},
+			"referenced_collections": referencedCollectionsSchema("query"),
 			"description": {
 				Description: "Text describing the collection.",
 				Type:        schema.TypeString,
@@ -60,6 +64,9 @@ func resourceView() *schema.Resource {
 				Computed:    true,
 			},
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(defaultWaitTimeout),
+		},
 	}
 }
 
@@ -74,7 +81,7 @@ func resourceViewCreate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	view, err := rc.CreateView(ctx, workspace, name, query, option.WithViewDescription(description))
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, diagnostics.SQL{Query: query, Path: []interface{}{"query"}})
 	}
 
 	err = d.Set("created_by", view.GetCreatorEmail())
@@ -102,7 +109,7 @@ func resourceViewUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 
 	_, err := rc.UpdateView(ctx, workspace, name, query, opts...)
 	if err != nil {
-		return DiagFromErr(err)
+		return DiagFromErr(err, diagnostics.SQL{Query: query, Path: []interface{}{"query"}})
 	}
 
 	return diags
@@ -153,7 +160,10 @@ func resourceViewDelete(ctx context.Context, d *schema.ResourceData, meta interf
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilViewGone(ctx, workspace, name)
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "view "+d.Id()+" to be deleted",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilViewGone(ctx, workspace, name)
+		}, viewState(rc, workspace, name))
 	if err != nil {
 		return DiagFromErr(err)
 	}
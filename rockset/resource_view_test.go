@@ -1,17 +1,21 @@
 package rockset
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
-	"github.com/rockset/rockset-go-client"
 	"github.com/rockset/rockset-go-client/openapi"
 )
 
 func TestAccView_Basic(t *testing.T) {
+	useCassette(t)
+
 	var view openapi.View
 
 	name := randomName("view")
@@ -57,7 +61,7 @@ func TestAccView_Basic(t *testing.T) {
 }
 
 func testAccCheckRocksetViewDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_view" {
@@ -79,7 +83,7 @@ func testAccCheckRocksetViewDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetViewExists(resource string, view *openapi.View) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -98,3 +102,37 @@ func testAccCheckRocksetViewExists(resource string, view *openapi.View) resource
 		return nil
 	}
 }
+
+func TestView_QueryFormatting(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "commons.recent",
+		Attributes: map[string]string{
+			"id":          "commons.recent",
+			"name":        "recent",
+			"workspace":   "commons",
+			"query":       "SELECT * FROM commons._events WHERE kind = 'a'",
+			"description": "created by Rockset terraform provider",
+			"created_by":  "me@example.com",
+
+			"referenced_collections.#": "1",
+			"referenced_collections.0": "commons._events",
+		},
+	}
+	config := func(query string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":      "recent",
+			"workspace": "commons",
+			"query":     query,
+		})
+	}
+
+	diff, err := resourceView().Diff(context.TODO(), state,
+		config("select *\n  from commons._events\n  where kind = 'a';\n"), nil)
+	require.NoError(t, err)
+	assert.True(t, diff.Empty(), "reformatted query")
+
+	diff, err = resourceView().Diff(context.TODO(), state,
+		config("SELECT * FROM commons._events WHERE kind = 'b'"), nil)
+	require.NoError(t, err)
+	assert.NotNil(t, diff.Attributes["query"], "changed query")
+}
@@ -25,9 +25,10 @@ which must be SMALL or larger. To enable live mount, the secondary virtual insta
 		UpdateContext: resourceVirtualInstanceUpdate,
 		DeleteContext: resourceVirtualInstanceDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(idFormat{
+			parts:   []idPart{{attribute: "id"}},
+			example: "29e4a43c-fff4-4fe6-80e3-1ee57bc22e82",
+		}, resourceVirtualInstanceRead),
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -96,6 +97,11 @@ which must be SMALL or larger. To enable live mount, the secondary virtual insta
 			},
 			// TODO scaled_pod_count has no documentation,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultWaitTimeout),
+			Update: schema.DefaultTimeout(defaultWaitTimeout),
+			Delete: schema.DefaultTimeout(defaultWaitTimeout),
+		},
 	}
 }
 
@@ -115,7 +121,10 @@ func resourceVirtualInstanceCreate(ctx context.Context, d *schema.ResourceData,
 	d.SetId(id)
 
 	// TODO make it possible to skip waiting, and then parse the fields from the created vi
-	err = rc.Wait.UntilVirtualInstanceActive(ctx, id)
+	err = waitWithTimeout(ctx, d, schema.TimeoutCreate, "virtual instance "+id+" to be active",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilVirtualInstanceActive(ctx, id)
+		}, virtualInstanceState(rc, id))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -140,7 +149,7 @@ func resourceVirtualInstanceRead(ctx context.Context, d *schema.ResourceData, me
 
 	vi, err := rc.GetVirtualInstance(ctx, id)
 	if err != nil {
-		return DiagFromErr(err)
+		return checkForNotFoundError(d, err)
 	}
 
 	if err = parseVirtualInstanceFields(vi, d); err != nil {
@@ -164,7 +173,10 @@ func resourceVirtualInstanceUpdate(ctx context.Context, d *schema.ResourceData,
 	}
 
 	// TODO make it possible to skip waiting
-	err = rc.Wait.UntilVirtualInstanceActive(ctx, id)
+	err = waitWithTimeout(ctx, d, schema.TimeoutUpdate, "virtual instance "+id+" to be active",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilVirtualInstanceActive(ctx, id)
+		}, virtualInstanceState(rc, id))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -192,7 +204,10 @@ func resourceVirtualInstanceDelete(ctx context.Context, d *schema.ResourceData,
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilVirtualInstanceGone(ctx, id)
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "virtual instance "+id+" to be deleted",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilVirtualInstanceGone(ctx, id)
+		}, virtualInstanceState(rc, id))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -1,6 +1,7 @@
 package rockset
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"testing"
@@ -12,6 +13,8 @@ import (
 )
 
 func TestAccVirtualInstance_Basic(t *testing.T) {
+	useCassette(t)
+
 	vi := "rockset_virtual_instance.test"
 	mount := "rockset_collection_mount.patch"
 
@@ -21,8 +24,9 @@ func TestAccVirtualInstance_Basic(t *testing.T) {
 		Size        string
 		Remount     bool
 	}
-	v1 := cfg{"small", "v1 desc", "SMALL", true}
-	v2 := cfg{"medium", "v2 desc", "MEDIUM", false}
+	var viID string
+	v1 := cfg{randomName("vi"), "v1 desc", "SMALL", true}
+	v2 := cfg{randomName("vi"), "v2 desc", "MEDIUM", false}
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccPreCheck(t) },
@@ -66,14 +70,29 @@ func TestAccVirtualInstance_Basic(t *testing.T) {
 					resource.TestCheckResourceAttr(vi, "state", "ACTIVE"),
 					// mount
 					resource.TestCheckResourceAttr(mount, "state", "ACTIVE"),
+					testAccStoreID(vi, &viID),
 				),
 			},
+			testAccDeletedStep(t, getHCLTemplate("virtual_instance_basic.tf", v2),
+				func(ctx context.Context, rc *rockset.RockClient) error {
+					if _, err := rc.UnmountCollection(ctx, viID, "persistent.patch"); err != nil {
+						return err
+					}
+					return rc.Wait.UntilMountGone(ctx, viID, "persistent", "patch")
+				}),
+			testAccDeletedStep(t, getHCLTemplate("virtual_instance_basic.tf", v2),
+				func(ctx context.Context, rc *rockset.RockClient) error {
+					if _, err := rc.DeleteVirtualInstance(ctx, viID); err != nil {
+						return err
+					}
+					return rc.Wait.UntilVirtualInstanceGone(ctx, viID)
+				}),
 		},
 	})
 }
 
 func testAccCheckRocksetVirtualInstanceDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_virtual_instance" {
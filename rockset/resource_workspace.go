@@ -11,16 +11,15 @@ import (
 )
 
 func resourceWorkspace() *schema.Resource {
-	return &schema.Resource{
+	return withDeletionProtection("workspace", &schema.Resource{
 		Description: "Manages a Rockset workspace, which can hold collections, query lambdas and views.",
 
 		CreateContext: resourceWorkspaceCreate,
 		ReadContext:   resourceWorkspaceRead,
+		UpdateContext: resourceWorkspaceUpdate,
 		DeleteContext: resourceWorkspaceDelete,
 
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer: importer(nameIDFormat("commons"), resourceWorkspaceRead),
 
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -42,6 +41,15 @@ func resourceWorkspace() *schema.Resource {
 				ForceNew:    true,
 				Optional:    true,
 			},
+			"force_destroy": {
+				Description: "Delete everything in the workspace when the workspace is destroyed: query lambdas, " +
+					"views, aliases and collections, after unmounting the collections from all virtual instances. " +
+					"Scheduled lambdas can't be listed, so those not managed by Terraform must be deleted first. " +
+					"It must be enabled in a separate apply before the workspace is destroyed.",
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"created_by": {
 				Description: "The user who created the workspace.",
 				Type:        schema.TypeString,
@@ -58,7 +66,10 @@ func resourceWorkspace() *schema.Resource {
 				Computed:    true,
 			},
 		},
-	}
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(defaultWaitTimeout),
+		},
+	})
 }
 
 func resourceWorkspaceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -101,7 +112,7 @@ func resourceWorkspaceRead(ctx context.Context, d *schema.ResourceData, meta int
 
 	workspace, err := rc.GetWorkspace(ctx, name)
 	if err != nil {
-		return DiagFromErr(err)
+		return checkForNotFoundError(d, err)
 	}
 
 	err = d.Set("name", workspace.GetName())
@@ -132,18 +143,33 @@ func resourceWorkspaceRead(ctx context.Context, d *schema.ResourceData, meta int
 	return diags
 }
 
+// resourceWorkspaceUpdate only updates the state, as deletion_protection and force_destroy are the only attributes
+// which can change without replacing the workspace.
+func resourceWorkspaceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceWorkspaceRead(ctx, d, meta)
+}
+
 func resourceWorkspaceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	rc := meta.(*rockset.RockClient)
 	var diags diag.Diagnostics
 
 	name := d.Id()
 
+	if d.Get("force_destroy").(bool) {
+		if err := deleteWorkspaceContents(ctx, rc, d, name); err != nil {
+			return DiagFromErr(err)
+		}
+	}
+
 	err := rc.DeleteWorkspace(ctx, name)
 	if err != nil {
 		return DiagFromErr(err)
 	}
 
-	err = rc.Wait.UntilWorkspaceGone(ctx, name)
+	err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "workspace "+name+" to be deleted",
+		func(ctx context.Context) error {
+			return rc.Wait.UntilWorkspaceGone(ctx, name)
+		}, workspaceState(rc, name))
 	if err != nil {
 		return DiagFromErr(err)
 	}
@@ -1,6 +1,7 @@
 package rockset
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -11,6 +12,8 @@ import (
 )
 
 func TestAccWorkspace_Basic(t *testing.T) {
+	useCassette(t)
+
 	var workspace openapi.Workspace
 
 	type values struct {
@@ -43,12 +46,20 @@ func TestAccWorkspace_Basic(t *testing.T) {
 				),
 				ExpectNonEmptyPlan: false,
 			},
+			testAccDeletedStep(t,
+				getHCLTemplate("workspace_basic.tf", values{"acc-ws-updated", "Terraform provider acceptance tests"}),
+				func(ctx context.Context, rc *rockset.RockClient) error {
+					if err := rc.DeleteWorkspace(ctx, "acc-ws-updated"); err != nil {
+						return err
+					}
+					return rc.Wait.UntilWorkspaceGone(ctx, "acc-ws-updated")
+				}),
 		},
 	})
 }
 
 func testAccCheckRocksetWorkspaceDestroy(s *terraform.State) error {
-	rc := testAccProvider.Meta().(*rockset.RockClient)
+	rc := testAccClient()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "rockset_api_key" {
@@ -70,7 +81,7 @@ func testAccCheckRocksetWorkspaceDestroy(s *terraform.State) error {
 
 func testAccCheckRocksetWorkspaceExists(resource string, workspace *openapi.Workspace) resource.TestCheckFunc {
 	return func(state *terraform.State) error {
-		rc := testAccProvider.Meta().(*rockset.RockClient)
+		rc := testAccClient()
 
 		rs, err := getResourceFromState(state, resource)
 		if err != nil {
@@ -0,0 +1,305 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/openapi"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+const suspendedDescription = "Suspend ingesting from the source, e.g. during maintenance of the upstream system. " +
+	"Rockset resumes the source at `resume_at`, which isn't reported as a change, so it isn't suspended again " +
+	"until `resume_after` changes. Set it to false once the source should stay resumed. A source which is resumed " +
+	"outside of Terraform before `resume_at` is suspended again by the next apply."
+
+const resumeAtDescription = "When Rockset resumes the suspended source, as an RFC 3339 timestamp."
+
+const resumeAfterDescription = "How long the source is suspended for before Rockset resumes it, " +
+	"as a number of seconds, minutes or hours, e.g. `30m` or `12h`. Rockset resumes it after an hour by default."
+
+var resumeAfterValidator = validation.StringMatch(client.ResumeAfterRe,
+	"must be a number of seconds, minutes or hours, e.g. 30m or 12h")
+
+// sourceSuspensionAttributes are the attributes of a source block which can change without replacing the source.
+var sourceSuspensionAttributes = []string{"id", "suspended", "resume_after", "resume_at"}
+
+// withSourceSuspension adds the suspended, resume_after and resume_at attributes to the source blocks of a
+// collection, and suspends and resumes the sources in place when they change. Changing any other attribute of a
+// source block still replaces the collection.
+func withSourceSuspension(r *schema.Resource) *schema.Resource {
+	source := r.Schema["source"]
+	elem := source.Elem.(*schema.Resource)
+
+	elem.Schema["id"] = &schema.Schema{
+		Description: "ID of the source.",
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+	elem.Schema["suspended"] = &schema.Schema{
+		Description: suspendedDescription,
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+	elem.Schema["resume_after"] = &schema.Schema{
+		Description:  resumeAfterDescription,
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: resumeAfterValidator,
+	}
+	elem.Schema["resume_at"] = &schema.Schema{
+		Description: resumeAtDescription,
+		Type:        schema.TypeString,
+		Computed:    true,
+	}
+
+	// suspending a source changes its block, which must not replace the collection, so the blocks only force
+	// a replacement when the sources they identify change
+	identity := sourceIdentity(source)
+	source.ForceNew = false
+	clearForceNew(elem.Schema)
+	addReplacementPredicate(r, "source", func(d *schema.ResourceDiff) bool { return sourcesReplaced(d, identity) })
+	addCustomizeDiff(r, func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+		if d.Id() == "" || !sourcesReplaced(d, identity) {
+			return nil
+		}
+		return forceNewSource(d, elem)
+	})
+
+	if r.Timeouts != nil && r.Timeouts.Update == nil {
+		r.Timeouts.Update = schema.DefaultTimeout(defaultWaitTimeout)
+	}
+
+	// resume_after isn't returned by the API, so it is kept from the state, as is a suspension which Rockset ended
+	// at resume_at, which only is drift when the source is resumed before then
+	read := r.ReadContext
+	r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		prior := d.Get("source").(*schema.Set)
+		diags := read(ctx, d, meta)
+		if diags.HasError() || d.Id() == "" {
+			return diags
+		}
+
+		priorSources := make(map[int]map[string]interface{})
+		for _, v := range prior.List() {
+			priorSources[identity(v)] = v.(map[string]interface{})
+		}
+
+		now := time.Now()
+		current := d.Get("source").(*schema.Set)
+		sources := make([]interface{}, 0, current.Len())
+		for _, v := range current.List() {
+			src := v.(map[string]interface{})
+			if p, found := priorSources[identity(v)]; found {
+				src["resume_after"] = p["resume_after"]
+				if resumeAt, _ := p["resume_at"].(string); p["suspended"] == true && src["suspended"] == false &&
+					client.ResumedAutomatically(resumeAt, now) {
+					src["suspended"] = true
+					src["resume_at"] = resumeAt
+				}
+			}
+			sources = append(sources, src)
+		}
+
+		return append(diags, diag.FromErr(d.Set("source", sources))...)
+	}
+
+	create := r.CreateContext
+	r.CreateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		desired := d.Get("source").(*schema.Set)
+		diags := create(ctx, d, meta)
+		if diags.HasError() || !anySourceSuspended(desired) {
+			return diags
+		}
+
+		// the IDs of the sources are only known once the collection has been read
+		if diags = append(diags, r.ReadContext(ctx, d, meta)...); diags.HasError() {
+			return diags
+		}
+
+		err := reconcileSourceSuspension(ctx, meta.(*rockset.RockClient), d, schema.TimeoutCreate,
+			identity, d.Get("source").(*schema.Set), desired)
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	update := r.UpdateContext
+	r.UpdateContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		var diags diag.Diagnostics
+		if d.HasChangeExcept("source") {
+			if diags = update(ctx, d, meta); diags.HasError() {
+				return diags
+			}
+		}
+		if !d.HasChange("source") {
+			return diags
+		}
+
+		current, desired := d.GetChange("source")
+		err := reconcileSourceSuspension(ctx, meta.(*rockset.RockClient), d, schema.TimeoutUpdate,
+			identity, current.(*schema.Set), desired.(*schema.Set))
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return r
+}
+
+// sourceIdentity hashes a source block without its suspension, so blocks of the same source have the same identity.
+func sourceIdentity(source *schema.Schema) schema.SchemaSetFunc {
+	elem := source.Elem.(*schema.Resource)
+	s := make(map[string]*schema.Schema, len(elem.Schema))
+	for k, v := range elem.Schema {
+		s[k] = v
+	}
+	for _, k := range sourceSuspensionAttributes {
+		delete(s, k)
+	}
+
+	return schema.HashResource(&schema.Resource{Schema: s})
+}
+
+// sourcesReplaced returns true if the planned source blocks add, remove or change a source, rather than only
+// suspend or resume it.
+func sourcesReplaced(d *schema.ResourceDiff, identity schema.SchemaSetFunc) bool {
+	if !d.HasChange("source") {
+		return false
+	}
+	if !d.NewValueKnown("source") {
+		return true
+	}
+
+	count := func(v interface{}) map[int]int {
+		identities := make(map[int]int)
+		for _, src := range v.(*schema.Set).List() {
+			identities[identity(src)]++
+		}
+		return identities
+	}
+
+	o, n := d.GetChange("source")
+	return !reflect.DeepEqual(count(o), count(n))
+}
+
+// forceNewSource makes the planned source blocks replace the collection. As ForceNew of a set only applies when
+// its number of blocks changes, it is set on a required attribute of a block which is removed or added instead.
+func forceNewSource(d *schema.ResourceDiff, elem *schema.Resource) error {
+	var required string
+	for k, v := range elem.Schema {
+		if v.Required && (required == "" || k < required) {
+			required = k
+		}
+	}
+
+	o, n := d.GetChange("source")
+	for _, set := range []*schema.Set{o.(*schema.Set), n.(*schema.Set)} {
+		for _, v := range set.List() {
+			key := fmt.Sprintf("source.%d.%s", set.F(v), required)
+			if d.HasChange(key) {
+				return d.ForceNew(key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// clearForceNew removes ForceNew from the attributes, including those of nested blocks.
+func clearForceNew(s map[string]*schema.Schema) {
+	for _, v := range s {
+		v.ForceNew = false
+		if elem, ok := v.Elem.(*schema.Resource); ok {
+			clearForceNew(elem.Schema)
+		}
+	}
+}
+
+// flattenSourceSuspension sets the attributes which all source blocks have from the source.
+func flattenSourceSuspension(m map[string]interface{}, src openapi.Source) {
+	m["id"] = src.GetId()
+	m["suspended"] = client.IsSourceSuspended(src)
+	m["resume_at"] = ""
+	if client.IsSourceSuspended(src) {
+		m["resume_at"] = src.GetResumeAt()
+	}
+}
+
+func anySourceSuspended(sources *schema.Set) bool {
+	for _, v := range sources.List() {
+		if v.(map[string]interface{})["suspended"].(bool) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reconcileSourceSuspension suspends and resumes the current sources of the collection which differ from the
+// desired ones, and sets the source blocks to the result. A current and a desired block are the same source
+// when they have the same identity.
+func reconcileSourceSuspension(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData,
+	operation string, identity schema.SchemaSetFunc, current, desired *schema.Set) error {
+	want := make(map[int]map[string]interface{})
+	for _, v := range desired.List() {
+		want[identity(v)] = v.(map[string]interface{})
+	}
+
+	workspace, name := workspaceAndNameFromID(d.Id())
+	sources := make([]interface{}, 0, current.Len())
+	for _, v := range current.List() {
+		src := v.(map[string]interface{})
+		cfg, found := want[identity(v)]
+		if !found {
+			sources = append(sources, src)
+			continue
+		}
+
+		suspended := cfg["suspended"].(bool)
+		resumeAfter := cfg["resume_after"].(string)
+		resumeAt, _ := src["resume_at"].(string)
+		// suspending a suspended source again restarts resume_after, so it is only done when it has changed, and
+		// a source which Rockset has resumed already doesn't need to be resumed
+		changed := suspended != src["suspended"].(bool) || (suspended && resumeAfter != src["resume_after"])
+		if changed && !suspended && client.ResumedAutomatically(resumeAt, time.Now()) {
+			changed = false
+			resumeAt = ""
+		}
+
+		if changed {
+			id := src["id"].(string)
+			change := "resumed"
+			if suspended {
+				change = "suspended"
+			}
+
+			var updated openapi.Source
+			err := waitWithTimeout(ctx, d, operation,
+				fmt.Sprintf("source %s of collection %s to be %s", id, d.Id(), change),
+				func(ctx context.Context) error {
+					var err error
+					updated, err = client.SetSourceSuspended(ctx, rc, workspace, name, id, suspended, resumeAfter)
+					return err
+				},
+				func(ctx context.Context) (string, error) {
+					return client.SourceState(ctx, rc, workspace, name, id)
+				})
+			if err != nil {
+				return err
+			}
+			resumeAt = updated.GetResumeAt()
+		}
+
+		src["suspended"] = suspended
+		src["resume_after"] = resumeAfter
+		src["resume_at"] = resumeAt
+		sources = append(sources, src)
+	}
+
+	return d.Set("source", sources)
+}
@@ -0,0 +1,217 @@
+package rockset
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestSourceSuspension(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	sourceConfig := func(pattern string, suspended bool, protected bool) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace":           "commons",
+			"name":                "orders",
+			"deletion_protection": protected,
+			"source": []interface{}{
+				map[string]interface{}{
+					"bucket":       "orders",
+					"pattern":      pattern,
+					"format":       "json",
+					"suspended":    suspended,
+					"resume_after": "12h",
+				},
+			},
+		})
+	}
+	config := func(suspended bool) *terraform.ResourceConfig {
+		return sourceConfig("*.json", suspended, false)
+	}
+	source := func() openapi.Source {
+		c, err := rc.GetCollection(ctx, "commons", "orders")
+		require.NoError(t, err)
+		require.Len(t, c.Sources, 1)
+
+		return c.Sources[0]
+	}
+	apply := func(state *terraform.InstanceState, suspended bool) *terraform.InstanceState {
+		r := resourceS3Collection()
+		diff, err := r.Diff(ctx, state, config(suspended), rc)
+		require.NoError(t, err)
+		if state != nil {
+			require.False(t, diff.RequiresNew())
+		}
+
+		state, diags := r.Apply(ctx, state, diff, rc)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		return state
+	}
+
+	// a source which is suspended when the collection is created is suspended once it exists
+	state := apply(nil, true)
+	src := source()
+	assert.True(t, client.IsSourceSuspended(src))
+	assert.Equal(t, src.GetId(), sourceAttribute(t, state, "id"))
+	assert.Equal(t, "true", sourceAttribute(t, state, "suspended"))
+	assert.Equal(t, "12h", sourceAttribute(t, state, "resume_after"))
+
+	assert.Equal(t, src.GetResumeAt(), sourceAttribute(t, state, "resume_at"))
+
+	// resuming it outside of Terraform before resume_at is drift, which the next apply reverts
+	_, err := client.SetSourceSuspended(ctx, rc, "commons", "orders", src.GetId(), false, "")
+	require.NoError(t, err)
+	state, diags := resourceS3Collection().RefreshWithoutUpgrade(ctx, state, rc)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "false", sourceAttribute(t, state, "suspended"))
+	assert.Equal(t, "12h", sourceAttribute(t, state, "resume_after"))
+	assert.Empty(t, sourceAttribute(t, state, "resume_at"))
+
+	state = apply(state, true)
+	assert.True(t, client.IsSourceSuspended(source()))
+	assert.Equal(t, "true", sourceAttribute(t, state, "suspended"))
+
+	// resuming it updates the collection in place
+	state = apply(state, false)
+	assert.False(t, client.IsSourceSuspended(source()))
+	assert.Equal(t, "false", sourceAttribute(t, state, "suspended"))
+	assert.Equal(t, src.GetId(), sourceAttribute(t, state, "id"))
+
+	// changing what is ingested still replaces the collection
+	diff, err := resourceS3Collection().Diff(ctx, state, sourceConfig("*.csv", false, false), rc)
+	require.NoError(t, err)
+	assert.True(t, diff.RequiresNew())
+
+	added := sourceConfig("*.json", false, false)
+	added.Config["source"] = append(added.Config["source"].([]interface{}), map[string]interface{}{
+		"bucket": "orders", "pattern": "*.csv", "format": "json",
+	})
+	diff, err = resourceS3Collection().Diff(ctx, state, added, rc)
+	require.NoError(t, err)
+	assert.True(t, diff.RequiresNew())
+
+	// as does a source with a value which is unknown until apply, e.g. the name of an integration which is replaced
+	block := schema.InternalMap(resourceS3Collection().Schema).CoreConfigSchema()
+	raw, err := ctyjson.Unmarshal([]byte(`{
+		"workspace": "commons",
+		"name": "orders",
+		"source": [{"bucket": "orders", "pattern": "*.json", "format": "json", "integration_name": "unknown"}]
+	}`), block.ImpliedType())
+	require.NoError(t, err)
+	raw, err = cty.Transform(raw, func(_ cty.Path, v cty.Value) (cty.Value, error) {
+		if v.RawEquals(cty.StringVal("unknown")) {
+			return cty.UnknownVal(cty.String), nil
+		}
+		return v, nil
+	})
+	require.NoError(t, err)
+	diff, err = resourceS3Collection().Diff(ctx, state, terraform.NewResourceConfigShimmed(raw, block), rc)
+	require.NoError(t, err)
+	assert.True(t, diff.RequiresNew())
+
+	// which deletion protection prevents, while it allows suspending the source
+	protected := state.DeepCopy()
+	protected.Attributes["deletion_protection"] = "true"
+	_, err = resourceS3Collection().Diff(ctx, protected, sourceConfig("*.csv", false, true), rc)
+	assert.ErrorContains(t, err, "changing source requires replacing it")
+	_, err = resourceS3Collection().Diff(ctx, protected, sourceConfig("*.json", true, true), rc)
+	assert.NoError(t, err)
+}
+
+func TestSourceSuspension_AutomaticResume(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	config := func(suspended bool) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace": "commons",
+			"name":      "orders",
+			"source": []interface{}{
+				map[string]interface{}{
+					"bucket":       "orders",
+					"format":       "json",
+					"suspended":    suspended,
+					"resume_after": "1s",
+				},
+			},
+		})
+	}
+	apply := func(state *terraform.InstanceState, suspended bool) *terraform.InstanceState {
+		r := resourceS3Collection()
+		diff, err := r.Diff(ctx, state, config(suspended), rc)
+		require.NoError(t, err)
+
+		state, diags := r.Apply(ctx, state, diff, rc)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		return state
+	}
+	suspended := func() bool {
+		c, err := rc.GetCollection(ctx, "commons", "orders")
+		require.NoError(t, err)
+		require.Len(t, c.Sources, 1)
+
+		return client.IsSourceSuspended(c.Sources[0])
+	}
+
+	state := apply(nil, true)
+	resumeAt := sourceAttribute(t, state, "resume_at")
+	assert.NotEmpty(t, resumeAt)
+
+	// Rockset resuming the source at resume_at isn't drift, so the next apply doesn't suspend it again
+	time.Sleep(1100 * time.Millisecond)
+	assert.False(t, suspended())
+	state, diags := resourceS3Collection().RefreshWithoutUpgrade(ctx, state, rc)
+	require.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "true", sourceAttribute(t, state, "suspended"))
+	assert.Equal(t, resumeAt, sourceAttribute(t, state, "resume_at"))
+
+	diff, err := resourceS3Collection().Diff(ctx, state, config(true), rc)
+	require.NoError(t, err)
+	for k := range diff.Attributes {
+		assert.False(t, strings.HasPrefix(k, "source."), "unexpected change of %s", k)
+	}
+
+	// once it should stay resumed, it only is resumed in the state
+	state = apply(state, false)
+	assert.False(t, suspended())
+	assert.Equal(t, "false", sourceAttribute(t, state, "suspended"))
+	assert.Empty(t, sourceAttribute(t, state, "resume_at"))
+}
+
+// sourceAttribute returns an attribute of the only source block in the state.
+func sourceAttribute(t *testing.T, state *terraform.InstanceState, name string) string {
+	t.Helper()
+
+	var value string
+	var found int
+	for k, v := range state.Attributes {
+		if strings.HasPrefix(k, "source.") && strings.HasSuffix(k, "."+name) {
+			value = v
+			found++
+		}
+	}
+	require.Equal(t, 1, found, "source attribute %s", name)
+
+	return value
+}
@@ -0,0 +1,255 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/rockset/rockset-go-client/openapi"
+
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+	"github.com/rockset/terraform-provider-rockset/internal/sqlscan"
+)
+
+// sqlLint is a problem which the provider found in SQL, rather than Rockset when validating it.
+type sqlLint struct {
+	severity tfprotov5.DiagnosticSeverity
+	diagnostics.Diagnostic
+}
+
+func lintError(path []interface{}, summary, detail string) sqlLint {
+	return sqlLint{
+		severity:   tfprotov5.DiagnosticSeverityError,
+		Diagnostic: diagnostics.Diagnostic{Summary: summary, Detail: detail, Path: path},
+	}
+}
+
+func lintWarning(path []interface{}, summary, detail string) sqlLint {
+	return sqlLint{
+		severity:   tfprotov5.DiagnosticSeverityWarning,
+		Diagnostic: diagnostics.Diagnostic{Summary: summary, Detail: detail, Path: path},
+	}
+}
+
+// reportSQLLint adds the problems to the plan, and returns true if any of them is an error. The returned error
+// is only set when not planning through ProviderServer, as the errors can't be added to the plan then.
+func reportSQLLint(ctx context.Context, lints []sqlLint) (bool, error) {
+	var failed bool
+	var errs []error
+
+	for _, l := range lints {
+		if l.severity == tfprotov5.DiagnosticSeverityWarning {
+			tflog.Warn(ctx, l.Summary, map[string]interface{}{"detail": l.Detail})
+		} else {
+			failed = true
+		}
+
+		if !addPlanDiagnostic(ctx, l.Proto(l.severity)) && l.severity == tfprotov5.DiagnosticSeverityError {
+			errs = append(errs, fmt.Errorf("%s: %s", l.Summary, l.Detail))
+		}
+	}
+
+	return failed, errors.Join(errs...)
+}
+
+// lintUnterminated reports strings, quoted identifiers and comments which aren't closed, as nothing after them
+// can be checked.
+func lintUnterminated(query string, tokens []sqlscan.Token, path []interface{}) []sqlLint {
+	for _, t := range tokens {
+		if !t.Unterminated {
+			continue
+		}
+
+		what := "string"
+		switch t.Kind {
+		case sqlscan.QuotedIdentifier:
+			what = "quoted identifier"
+		case sqlscan.Comment:
+			what = "comment"
+		}
+
+		return []sqlLint{lintError(path, "unterminated "+what+" in the SQL",
+			diagnostics.Excerpt(query, t.Line, t.Column))}
+	}
+
+	return nil
+}
+
+// lintQueryLambdaSQL checks that the parameters of the query have a default_parameter, that every
+// default_parameter is used, and that the default values are of their type. Parameters without a default_parameter
+// and types the provider doesn't know are only warned about, as they are valid for Rockset.
+func lintQueryLambdaSQL(sql openapi.QueryLambdaSql) []sqlLint {
+	queryPath := []interface{}{"sql", 0, "query"}
+	paramPath := []interface{}{"sql", 0, "default_parameter"}
+
+	tokens := sqlscan.Tokenize(sql.Query)
+	if lints := lintUnterminated(sql.Query, tokens, queryPath); lints != nil {
+		return lints
+	}
+
+	var lints []sqlLint
+
+	params := append([]openapi.QueryParameter(nil), sql.DefaultParameters...)
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+
+	defaults := make(map[string]bool, len(params))
+	for _, p := range params {
+		defaults[p.Name] = true
+		err := validateParameterValue(p.Type, p.Value)
+		switch {
+		case errors.Is(err, errUnknownParameterType):
+			lints = append(lints, lintWarning(paramPath, fmt.Sprintf("unknown type of default_parameter %s", p.Name),
+				err.Error()+", so its value isn't checked and the query lambda isn't validated when planning."))
+		case err != nil:
+			lints = append(lints, lintError(paramPath, fmt.Sprintf("invalid default_parameter %s", p.Name),
+				err.Error()))
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, t := range sqlscan.StripComments(tokens) {
+		if t.Kind != sqlscan.Parameter || used[t.Name()] {
+			continue
+		}
+		used[t.Name()] = true
+
+		if !defaults[t.Name()] {
+			lints = append(lints, lintWarning(queryPath,
+				fmt.Sprintf("query parameter %s has no default_parameter", t.Name()),
+				"The query lambda must be executed with a value for it, and isn't validated when planning. "+
+					"Add a default_parameter block for it to make it optional.\n\n"+
+					diagnostics.Excerpt(sql.Query, t.Line, t.Column)))
+		}
+	}
+
+	for _, p := range params {
+		if !used[p.Name] {
+			lints = append(lints, lintWarning(paramPath,
+				fmt.Sprintf("default_parameter %s is not used by the query", p.Name),
+				fmt.Sprintf("The query doesn't reference :%s, so the default_parameter can be removed.", p.Name)))
+		}
+	}
+
+	return lints
+}
+
+// parametersBound returns true if every parameter of the query has a default_parameter of a known type, which is
+// needed to validate the query without executing it.
+func parametersBound(sql openapi.QueryLambdaSql) bool {
+	defaults := make(map[string]bool, len(sql.DefaultParameters))
+	for _, p := range sql.DefaultParameters {
+		if _, ok := parameterTypes[strings.ToLower(p.Type)]; ok {
+			defaults[p.Name] = true
+		}
+	}
+
+	for _, t := range sqlscan.StripComments(sqlscan.Tokenize(sql.Query)) {
+		if t.Kind == sqlscan.Parameter && !defaults[t.Name()] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parameterTypes are the types of query lambda parameters, and how their values are parsed.
+var parameterTypes = map[string]func(value string) error{
+	"string": func(string) error { return nil },
+	"int": func(value string) error {
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err
+	},
+	"float": func(value string) error {
+		_, err := strconv.ParseFloat(value, 64)
+		return err
+	},
+	"bool": func(value string) error {
+		if value != "true" && value != "false" {
+			return errors.New("must be true or false")
+		}
+		return nil
+	},
+	"date":      parseTime("2006-01-02"),
+	"datetime":  parseTime("2006-01-02T15:04:05.999999"),
+	"time":      parseTime("15:04:05.999999"),
+	"timestamp": parseTime(time.RFC3339Nano),
+}
+
+func parseTime(layout string) func(value string) error {
+	return func(value string) error {
+		if _, err := time.Parse(layout, value); err != nil {
+			return fmt.Errorf("must be formatted as %s", layout)
+		}
+		return nil
+	}
+}
+
+// errUnknownParameterType is returned by validateParameterValue for a type which isn't one of the parameterTypes.
+var errUnknownParameterType = errors.New("unknown parameter type")
+
+// validateParameterValue checks that typ is a query lambda parameter type, and that value is of the type.
+func validateParameterValue(typ, value string) error {
+	parse, ok := parameterTypes[strings.ToLower(typ)]
+	if !ok {
+		types := make([]string, 0, len(parameterTypes))
+		for t := range parameterTypes {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+
+		return fmt.Errorf("%w %q, the provider knows %s", errUnknownParameterType, typ, strings.Join(types, ", "))
+	}
+
+	if err := parse(value); err != nil {
+		return fmt.Errorf("value %q is not a valid %s: %w", value, strings.ToLower(typ), err)
+	}
+
+	return nil
+}
+
+// lintIngestTransformation checks that the ingest transformation is a query of the _input of the collection.
+func lintIngestTransformation(query string) []sqlLint {
+	path := []interface{}{"ingest_transformation"}
+
+	tokens := sqlscan.Tokenize(query)
+	if lints := lintUnterminated(query, tokens, path); lints != nil {
+		return lints
+	}
+	tokens = sqlscan.StripComments(tokens)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	var lints []sqlLint
+	if first := tokens[0]; !first.IsKeyword("SELECT") && !first.IsKeyword("WITH") {
+		lints = append(lints, lintError(path, "ingest_transformation must be a SELECT query",
+			diagnostics.Excerpt(query, first.Line, first.Column)))
+	}
+
+	var fromInput bool
+	for i, t := range tokens {
+		switch {
+		case t.Kind == sqlscan.Parameter:
+			lints = append(lints, lintError(path, "ingest_transformation can't have parameters",
+				diagnostics.Excerpt(query, t.Line, t.Column)))
+		case t.IsKeyword("FROM") && i+1 < len(tokens) && isInput(tokens[i+1]):
+			fromInput = true
+		}
+	}
+	if !fromInput {
+		lints = append(lints, lintError(path, "ingest_transformation must select FROM _input",
+			"The documents being ingested are only available as _input, e.g. SELECT * FROM _input."))
+	}
+
+	return lints
+}
+
+func isInput(t sqlscan.Token) bool {
+	return (t.Kind == sqlscan.Identifier || t.Kind == sqlscan.QuotedIdentifier) && t.Name() == "_input"
+}
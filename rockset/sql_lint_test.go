@@ -0,0 +1,225 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintQueryLambdaSQL(t *testing.T) {
+	param := func(name, typ, value string) openapi.QueryParameter {
+		return openapi.QueryParameter{Name: name, Type: typ, Value: value}
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		params    []openapi.QueryParameter
+		summaries []string
+	}{
+		{
+			name:  "bound parameters",
+			query: "SELECT * FROM commons._events WHERE _event_time > :start LIMIT :limit",
+			params: []openapi.QueryParameter{
+				param("limit", "int", "10"), param("start", "timestamp", "2020-01-01T00:00:00Z"),
+			},
+		},
+		{
+			name:      "unbound parameter",
+			query:     "SELECT * FROM commons._events LIMIT :limit",
+			summaries: []string{"query parameter limit has no default_parameter"},
+		},
+		{
+			name:      "unused default_parameter",
+			query:     "SELECT * FROM commons._events -- :limit\nLIMIT 10",
+			params:    []openapi.QueryParameter{param("limit", "int", "10")},
+			summaries: []string{"default_parameter limit is not used by the query"},
+		},
+		{
+			name:      "parameter in a string",
+			query:     "SELECT * FROM commons._events WHERE kind = ':kind'",
+			params:    []openapi.QueryParameter{param("kind", "string", "click")},
+			summaries: []string{"default_parameter kind is not used by the query"},
+		},
+		{
+			name:  "invalid default_parameter",
+			query: "SELECT * FROM commons._events WHERE _event_time > :start LIMIT :limit",
+			params: []openapi.QueryParameter{
+				param("limit", "int", "ten"), param("start", "time", "2020-01-01"),
+			},
+			summaries: []string{"invalid default_parameter limit", "invalid default_parameter start"},
+		},
+		{
+			name:      "unknown type",
+			query:     "SELECT * FROM commons._events WHERE _event_time > :start",
+			params:    []openapi.QueryParameter{param("start", "start", "2020-01-01")},
+			summaries: []string{"unknown type of default_parameter start"},
+		},
+		{
+			name:      "unterminated string",
+			query:     "SELECT * FROM commons._events WHERE kind = 'click LIMIT :limit",
+			summaries: []string{"unterminated string in the SQL"},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			sql := openapi.QueryLambdaSql{Query: tst.query, DefaultParameters: tst.params}
+
+			var summaries []string
+			for _, l := range lintQueryLambdaSQL(sql) {
+				summaries = append(summaries, l.Summary)
+			}
+			assert.Equal(t, tst.summaries, summaries)
+		})
+	}
+}
+
+func TestParametersBound(t *testing.T) {
+	query := "SELECT * FROM commons._events WHERE _event_time > :start LIMIT :limit"
+	limit := openapi.QueryParameter{Name: "limit", Type: "int", Value: "10"}
+
+	assert.True(t, parametersBound(openapi.QueryLambdaSql{Query: "SELECT 1"}))
+	assert.True(t, parametersBound(openapi.QueryLambdaSql{Query: query, DefaultParameters: []openapi.QueryParameter{
+		limit, {Name: "start", Type: "timestamp", Value: "2020-01-01T00:00:00Z"},
+	}}))
+	assert.False(t, parametersBound(openapi.QueryLambdaSql{Query: query,
+		DefaultParameters: []openapi.QueryParameter{limit}}))
+	assert.False(t, parametersBound(openapi.QueryLambdaSql{Query: query, DefaultParameters: []openapi.QueryParameter{
+		limit, {Name: "start", Type: "start", Value: "2020-01-01T00:00:00Z"},
+	}}))
+}
+
+func TestLintQueryLambdaSQL_Excerpt(t *testing.T) {
+	lints := lintQueryLambdaSQL(openapi.QueryLambdaSql{Query: "SELECT *\nFROM commons._events\nLIMIT :limit"})
+	require.Len(t, lints, 1)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, lints[0].severity)
+	assert.Equal(t, []interface{}{"sql", 0, "query"}, lints[0].Path)
+	assert.Contains(t, lints[0].Detail, "3 | LIMIT :limit\n  |       ^")
+}
+
+func TestValidateParameterValue(t *testing.T) {
+	for _, valid := range [][2]string{
+		{"string", "anything"},
+		{"int", "-42"},
+		{"float", "4.2e1"},
+		{"bool", "true"},
+		{"date", "2020-01-31"},
+		{"datetime", "2020-01-31T12:30:00.123456"},
+		{"time", "12:30:00"},
+		{"timestamp", "2020-01-31T12:30:00.000000Z"},
+		{"INT", "1"},
+	} {
+		assert.NoError(t, validateParameterValue(valid[0], valid[1]), valid)
+	}
+
+	for _, invalid := range [][2]string{
+		{"int", "4.2"},
+		{"float", "four"},
+		{"bool", "yes"},
+		{"date", "31/01/2020"},
+		{"timestamp", "2020-01-31"},
+	} {
+		assert.Error(t, validateParameterValue(invalid[0], invalid[1]), invalid)
+	}
+
+	err := validateParameterValue("start", "2020-01-31")
+	assert.ErrorIs(t, err, errUnknownParameterType)
+	assert.EqualError(t, err, `unknown parameter type "start", the provider knows `+
+		"bool, date, datetime, float, int, string, time, timestamp")
+}
+
+func TestLintIngestTransformation(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		summaries []string
+	}{
+		{
+			name:  "valid",
+			query: "SELECT LOWER(_input.name) AS lower, * FROM _input",
+		},
+		{
+			name:  "with",
+			query: "WITH e AS (SELECT * FROM _input) SELECT * FROM e",
+		},
+		{
+			name:  "quoted _input",
+			query: `select * from "_input" where x > 1`,
+		},
+		{
+			name:      "not from _input",
+			query:     "SELECT * FROM commons._events",
+			summaries: []string{"ingest_transformation must select FROM _input"},
+		},
+		{
+			name:      "_input in a comment",
+			query:     "SELECT * -- FROM _input\nFROM events",
+			summaries: []string{"ingest_transformation must select FROM _input"},
+		},
+		{
+			name:      "not a select",
+			query:     "INSERT INTO commons.orders SELECT * FROM _input",
+			summaries: []string{"ingest_transformation must be a SELECT query"},
+		},
+		{
+			name:      "parameter",
+			query:     "SELECT * FROM _input WHERE kind = :kind",
+			summaries: []string{"ingest_transformation can't have parameters"},
+		},
+		{
+			name:      "unterminated comment",
+			query:     "SELECT * FROM _input /* everything",
+			summaries: []string{"unterminated comment in the SQL"},
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			var summaries []string
+			for _, l := range lintIngestTransformation(tst.query) {
+				assert.Equal(t, []interface{}{"ingest_transformation"}, l.Path)
+				summaries = append(summaries, l.Summary)
+			}
+			assert.Equal(t, tst.summaries, summaries)
+		})
+	}
+}
+
+func TestSQLLintAtPlan(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace": "commons",
+		"name":      "recent",
+		"sql": []interface{}{
+			map[string]interface{}{
+				"query": "SELECT * FROM commons._events LIMIT 10",
+				"default_parameter": []interface{}{
+					map[string]interface{}{"name": "limit", "type": "int", "value": "10"},
+				},
+			},
+		},
+	})
+
+	// the lint doesn't need the client, so the SQL is checked even when Rockset can't be reached
+	collected := &planDiagnostics{}
+	ctx := context.WithValue(context.TODO(), planDiagnosticsKey{}, collected)
+	_, err := Provider().ResourcesMap["rockset_query_lambda"].Diff(ctx, nil, config, nil)
+	require.NoError(t, err)
+	require.Len(t, collected.diags, 1)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, collected.diags[0].Severity)
+	assert.Equal(t, "default_parameter limit is not used by the query", collected.diags[0].Summary)
+
+	// without ProviderServer errors fail the plan, and warnings are only logged
+	_, err = Provider().ResourcesMap["rockset_collection"].Diff(context.TODO(), nil,
+		terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace":             "commons",
+			"name":                  "orders",
+			"ingest_transformation": "SELECT * FROM commons._events",
+		}), nil)
+	assert.ErrorContains(t, err, "ingest_transformation must select FROM _input")
+}
@@ -0,0 +1,117 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/sqlscan"
+)
+
+// referencedCollectionsSchema is the schema of the collections, aliases and views which the SQL of the attribute
+// references, so modules can e.g. document the dependencies between them.
+func referencedCollectionsSchema(attribute string) *schema.Schema {
+	return &schema.Schema{
+		Description: fmt.Sprintf("Collections, aliases and views which `%s` references, as `workspace.name`.",
+			attribute),
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+func referencedCollections(query string) []string {
+	refs := make([]string, 0)
+	for _, ref := range sqlscan.References(query) {
+		refs = append(refs, ref.String())
+	}
+
+	return refs
+}
+
+// planReferencedCollections sets referenced_collections to the references of the SQL, if it is new or has changed.
+func planReferencedCollections(d *schema.ResourceDiff, sql planSQL) error {
+	if d.Id() != "" && !d.HasChange(sql.attribute) {
+		return nil
+	}
+	if !d.NewValueKnown(sql.attribute) {
+		return d.SetNewComputed("referenced_collections")
+	}
+
+	return d.SetNew("referenced_collections", referencedCollections(sql.sql.Query))
+}
+
+// readReferencedCollections makes the resource set referenced_collections from the SQL it reads, so it is set for
+// imported objects too.
+func readReferencedCollections(r *schema.Resource, sql func(d sqlGetter) planSQL) {
+	read := r.ReadContext
+	r.ReadContext = func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		diags := read(ctx, d, meta)
+		if diags.HasError() || d.Id() == "" {
+			return diags
+		}
+
+		if err := d.Set("referenced_collections", referencedCollections(sql(d).sql.Query)); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+
+		return diags
+	}
+}
+
+// warnMissingReferences warns about the references of the SQL which neither exist nor are in the configuration, as
+// creating the object then fails unless what it references is created first. It returns true if any reference
+// doesn't exist, whether or not it is in the configuration.
+func warnMissingReferences(ctx context.Context, rc *rockset.RockClient, sql planSQL,
+	configured *configuredRelations) bool {
+	var missing bool
+	var lints []sqlLint
+	for _, ref := range sqlscan.References(sql.sql.Query) {
+		exists, err := relationExists(ctx, rc, ref)
+		if err != nil {
+			addPlanWarning(ctx, "couldn't check the references of "+sql.attribute, err.Error())
+			return false
+		}
+		if exists {
+			continue
+		}
+
+		missing = true
+		if !configured.has(ctx, ref) {
+			lints = append(lints, lintWarning(sql.sql.Path,
+				fmt.Sprintf("%s references %s, which doesn't exist", sql.attribute, ref),
+				fmt.Sprintf("%s isn't a collection, alias or view, and the configuration doesn't create it "+
+					"either, so applying fails unless it is created first.", ref)))
+		}
+	}
+
+	// warnings never fail the plan
+	_, _ = reportSQLLint(ctx, lints)
+
+	return missing
+}
+
+// relationExists checks if the reference is a collection, alias or view.
+func relationExists(ctx context.Context, rc *rockset.RockClient, ref sqlscan.Reference) (bool, error) {
+	lookups := []func() error{
+		func() error { _, err := rc.GetCollection(ctx, ref.Workspace, ref.Name); return err },
+		func() error { _, err := rc.GetAlias(ctx, ref.Workspace, ref.Name); return err },
+		func() error { _, err := rc.GetView(ctx, ref.Workspace, ref.Name); return err },
+	}
+
+	for _, lookup := range lookups {
+		err := lookup()
+		if err == nil {
+			return true, nil
+		}
+		if !client.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,54 @@
+package rockset
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestReferencedCollections(t *testing.T) {
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	// the collection is in the configuration, so the view doesn't warn about it not existing yet, whether or not
+	// it is planned first
+	chdir(t, t.TempDir())
+	require.NoError(t, os.WriteFile("main.tf", []byte(`
+resource "rockset_collection" "orders" {
+  workspace = "commons"
+  name      = "orders"
+}
+`), 0o600))
+
+	p := Provider()
+	collected := &planDiagnostics{}
+	ctx := context.WithValue(context.TODO(), planDiagnosticsKey{}, collected)
+
+	diff, err := p.ResourcesMap["rockset_view"].Diff(ctx, nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace": "commons",
+		"name":      "recent",
+		"query":     "SELECT * FROM _events e JOIN commons.orders o ON e.order = o._id",
+	}), rc)
+	require.NoError(t, err)
+	assert.Empty(t, collected.diags)
+	assert.Equal(t, "2", diff.Attributes["referenced_collections.#"].New)
+	assert.Equal(t, "commons._events", diff.Attributes["referenced_collections.0"].New)
+	assert.Equal(t, "commons.orders", diff.Attributes["referenced_collections.1"].New)
+
+	// the attribute is set when reading too, e.g. for imported views
+	_, err = rc.CreateView(context.TODO(), "commons", "events", "SELECT * FROM commons._events")
+	require.NoError(t, err)
+
+	r := p.ResourcesMap["rockset_view"]
+	d := r.Data(&terraform.InstanceState{ID: "commons.events"})
+	require.False(t, r.ReadContext(context.TODO(), d, rc).HasError())
+	assert.Equal(t, []interface{}{"commons._events"}, d.Get("referenced_collections"))
+}
@@ -0,0 +1,197 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/diagnostics"
+)
+
+// planSQL is SQL which is validated when planning, and the attribute it comes from.
+type planSQL struct {
+	attribute string
+	sql       diagnostics.SQL
+	// params are bound when validating, so parameterized query lambdas validate too.
+	params []openapi.QueryParameter
+	// lint checks the SQL without sending it to Rockset, or is nil if there is nothing to check.
+	lint func() []sqlLint
+	// offline SQL only is linted, as Rockset can't validate it as a query.
+	offline bool
+}
+
+// sqlGetter gets attributes from the configuration, or the state.
+type sqlGetter interface {
+	Get(key string) interface{}
+}
+
+func ingestTransformationPlanSQL(d sqlGetter) planSQL {
+	query := d.Get("ingest_transformation").(string)
+
+	return planSQL{
+		attribute: "ingest_transformation",
+		sql:       diagnostics.SQL{Query: query, Path: []interface{}{"ingest_transformation"}},
+		lint:      func() []sqlLint { return lintIngestTransformation(query) },
+		// _input only exists while ingesting, so the transformation isn't a query which can be validated
+		offline: true,
+	}
+}
+
+// planSQLAttributes are the resources which have SQL to validate when planning.
+var planSQLAttributes = map[string]func(d sqlGetter) planSQL{
+	"rockset_view": func(d sqlGetter) planSQL {
+		return planSQL{
+			attribute: "query",
+			sql:       diagnostics.SQL{Query: d.Get("query").(string), Path: []interface{}{"query"}},
+		}
+	},
+	"rockset_query_lambda": func(d sqlGetter) planSQL {
+		sql := makeQueryLambdaSQL(d.Get("sql"))
+		return planSQL{
+			attribute: "sql",
+			sql:       diagnostics.SQL{Query: sql.Query, Path: []interface{}{"sql", 0, "query"}},
+			params:    sql.DefaultParameters,
+			lint:      func() []sqlLint { return lintQueryLambdaSQL(sql) },
+			// Rockset rejects the query without a value for every parameter, e.g. for one which is required
+			// when executing the query lambda
+			offline: !parametersBound(sql),
+		}
+	},
+	"rockset_collection":          ingestTransformationPlanSQL,
+	"rockset_dynamodb_collection": ingestTransformationPlanSQL,
+	"rockset_gcs_collection":      ingestTransformationPlanSQL,
+	"rockset_kafka_collection":    ingestTransformationPlanSQL,
+	"rockset_kinesis_collection":  ingestTransformationPlanSQL,
+	"rockset_mongodb_collection":  ingestTransformationPlanSQL,
+	"rockset_s3_collection":       ingestTransformationPlanSQL,
+}
+
+// validateSQLAtPlan makes the resources with SQL check it when planning, so errors are found before anything is
+// changed. The SQL is first checked offline, and if that finds no errors its references are looked up and the SQL of
+// views and query lambdas is sent to Rockset for validation, unless the provider is configured with
+// skip_sql_validation = true. It also keeps referenced_collections up to date with the SQL.
+// It must be called before resolveClient, as the validation needs the client.
+func validateSQLAtPlan(p *schema.Provider) {
+	var skip bool
+
+	configure := p.ConfigureContextFunc
+	p.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		skip = d.Get("skip_sql_validation").(bool)
+		return configure(ctx, d)
+	}
+
+	configured := &configuredRelations{dir: "."}
+
+	for name, sql := range planSQLAttributes {
+		sql := sql
+		readReferencedCollections(p.ResourcesMap[name], sql)
+		addCustomizeDiff(p.ResourcesMap[name], func(ctx context.Context, d *schema.ResourceDiff,
+			meta interface{}) error {
+			sql := sql(d)
+			if err := planReferencedCollections(d, sql); err != nil {
+				return err
+			}
+			if !planSQLChanged(d, sql) {
+				return nil
+			}
+
+			if sql.lint != nil {
+				if failed, err := reportSQLLint(ctx, sql.lint()); failed {
+					return err
+				}
+			}
+
+			rc, ok := meta.(*rockset.RockClient)
+			if !ok {
+				return nil
+			}
+
+			// the references are looked up even when the SQL isn't validated, as they can be checked without it
+			missing := warnMissingReferences(ctx, rc, sql, configured)
+			if skip || sql.offline {
+				return nil
+			}
+
+			return validatePlanSQL(ctx, rc, sql, missing)
+		})
+	}
+}
+
+// planSQLChanged returns true if the SQL is new or has changed, and is known.
+func planSQLChanged(d *schema.ResourceDiff, sql planSQL) bool {
+	if d.Id() != "" && !d.HasChange(sql.attribute) {
+		return false
+	}
+
+	return d.NewValueKnown(sql.attribute) && sql.sql.Query != ""
+}
+
+// validatePlanSQL sends the SQL to Rockset for validation. Only errors in the SQL itself fail the plan,
+// as the SQL can't be validated if it e.g. references a collection which is created by the same apply.
+// missing is true if the SQL references an object which doesn't exist.
+func validatePlanSQL(ctx context.Context, rc *rockset.RockClient, sql planSQL, missing bool) error {
+	var options []option.QueryOption
+	for _, p := range sql.params {
+		options = append(options, option.WithParameter(p.Name, p.Type, p.Value))
+	}
+
+	_, err := rc.ValidateQuery(ctx, sql.sql.Query, options...)
+	switch {
+	case err == nil:
+		return nil
+	// the message of the error isn't relied on to tell if the SQL references an object which doesn't exist,
+	// as the references are looked up too
+	case referencesMissingObject(err) || (isInvalidSQL(err) && missing):
+		tflog.Debug(ctx, "skipping SQL validation, as it references an object which doesn't exist yet",
+			map[string]interface{}{"attribute": sql.attribute, "error": err.Error()})
+		return nil
+	case isInvalidSQL(err):
+		return planError(ctx, err, sql.sql)
+	default:
+		addPlanWarning(ctx, "couldn't validate the SQL of "+sql.attribute,
+			"The SQL is validated when applying instead: "+err.Error()+". "+
+				"Set skip_sql_validation = true in the provider configuration to not validate SQL when planning.")
+		return nil
+	}
+}
+
+// isInvalidSQL returns true if Rockset rejected the SQL as invalid.
+func isInvalidSQL(err error) bool {
+	var re rockerr.Error
+	if !errors.As(err, &re) {
+		return false
+	}
+
+	return re.StatusCode == http.StatusBadRequest
+}
+
+// missingObjectRe matches the messages of Rockset errors about a workspace, collection, alias or view which doesn't
+// exist, e.g. "Collection 'commons.orders' not found".
+var missingObjectRe = regexp.MustCompile(
+	`(?i)\b(?:workspace|collection|alias|view)\s+['"]?[A-Za-z0-9_.-]+['"]?\s+(?:was\s+)?(?:not found|does not exist)`)
+
+// referencesMissingObject returns true if the SQL is rejected because it references a workspace, collection,
+// view or alias which doesn't exist. Rockset reports those as invalid SQL too, so the message is checked.
+// Other objects which don't exist, e.g. a misspelled function, are errors in the SQL.
+func referencesMissingObject(err error) bool {
+	if client.IsNotFound(err) {
+		return true
+	}
+
+	var re rockerr.Error
+	if !errors.As(err, &re) || re.ErrorModel == nil {
+		return false
+	}
+
+	return missingObjectRe.MatchString(re.GetMessage())
+}
@@ -0,0 +1,229 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestValidateSQLAtPlan(t *testing.T) {
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	view := func(query string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace": "commons",
+			"name":      "recent",
+			"query":     query,
+		})
+	}
+	queryLambda := func(query string, params ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"workspace": "commons",
+			"name":      "recent",
+			"sql": []interface{}{
+				map[string]interface{}{"query": query, "default_parameter": params},
+			},
+		})
+	}
+
+	tests := []struct {
+		name     string
+		resource string
+		config   *terraform.ResourceConfig
+		path     *tftypes.AttributePath
+		severity tfprotov5.DiagnosticSeverity
+		detail   string
+	}{
+		{
+			name:     "valid view",
+			resource: "rockset_view",
+			config:   view("SELECT * FROM commons._events"),
+		},
+		{
+			name:     "invalid view",
+			resource: "rockset_view",
+			config:   view("SELEC *\nFROM commons._events"),
+			path:     tftypes.NewAttributePath().WithAttributeName("query"),
+			detail:   "1 | SELEC *\n  | ^",
+		},
+		{
+			name:     "view of a collection which doesn't exist",
+			resource: "rockset_view",
+			config:   view("SELECT * FROM commons._events e JOIN commons.orders o ON e.order = o._id"),
+			path:     tftypes.NewAttributePath().WithAttributeName("query"),
+			severity: tfprotov5.DiagnosticSeverityWarning,
+			detail:   "commons.orders isn't a collection, alias or view",
+		},
+		{
+			// Rockset rejects the SQL for another reason, but as the collection doesn't exist the error could be
+			// caused by it not existing yet
+			name:     "invalid view of a collection which doesn't exist",
+			resource: "rockset_view",
+			config:   view("SELEC * FROM commons.orders"),
+			path:     tftypes.NewAttributePath().WithAttributeName("query"),
+			severity: tfprotov5.DiagnosticSeverityWarning,
+			detail:   "commons.orders isn't a collection, alias or view",
+		},
+		{
+			name:     "query lambda with bound parameter",
+			resource: "rockset_query_lambda",
+			config: queryLambda("SELECT * FROM commons._events LIMIT :limit", map[string]interface{}{
+				"name": "limit", "type": "int", "value": "10",
+			}),
+		},
+		{
+			name:     "query lambda with unbound parameter",
+			resource: "rockset_query_lambda",
+			config:   queryLambda("SELECT * FROM commons._events\nLIMIT :limit"),
+			path: tftypes.NewAttributePath().WithAttributeName("sql").WithElementKeyInt(0).
+				WithAttributeName("query"),
+			severity: tfprotov5.DiagnosticSeverityWarning,
+			detail:   "2 | LIMIT :limit\n  |       ^",
+		},
+		{
+			name:     "query lambda with parameter of an unknown type",
+			resource: "rockset_query_lambda",
+			config: queryLambda("SELECT * FROM commons._events WHERE _event_time > :start",
+				map[string]interface{}{"name": "start", "type": "start", "value": "2020-01-01T00:00:00.000000Z"}),
+			path: tftypes.NewAttributePath().WithAttributeName("sql").WithElementKeyInt(0).
+				WithAttributeName("default_parameter"),
+			severity: tfprotov5.DiagnosticSeverityWarning,
+			detail:   `unknown parameter type "start"`,
+		},
+		{
+			name:     "ingest transformation",
+			resource: "rockset_collection",
+			config: terraform.NewResourceConfigRaw(map[string]interface{}{
+				"workspace":             "commons",
+				"name":                  "orders",
+				"ingest_transformation": "SELECT * FROM commons._events",
+			}),
+			path:   tftypes.NewAttributePath().WithAttributeName("ingest_transformation"),
+			detail: "SELECT * FROM _input",
+		},
+		{
+			// _input can't be queried, so ingest transformations aren't sent to Rockset
+			name:     "valid ingest transformation",
+			resource: "rockset_collection",
+			config: terraform.NewResourceConfigRaw(map[string]interface{}{
+				"workspace":             "commons",
+				"name":                  "orders",
+				"ingest_transformation": "SELECT * FROM _input WHERE id IN (SELECT id FROM commons._events)",
+			}),
+		},
+		{
+			name:     "ingest transformation of a collection which doesn't exist",
+			resource: "rockset_collection",
+			config: terraform.NewResourceConfigRaw(map[string]interface{}{
+				"workspace":             "commons",
+				"name":                  "orders",
+				"ingest_transformation": "SELECT * FROM _input WHERE id IN (SELECT id FROM commons.customers)",
+			}),
+			path:     tftypes.NewAttributePath().WithAttributeName("ingest_transformation"),
+			severity: tfprotov5.DiagnosticSeverityWarning,
+			detail:   "commons.customers isn't a collection, alias or view",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			collected := &planDiagnostics{}
+			ctx := context.WithValue(context.TODO(), planDiagnosticsKey{}, collected)
+
+			_, err := Provider().ResourcesMap[tst.resource].Diff(ctx, nil, tst.config, rc)
+			require.NoError(t, err)
+
+			if tst.path == nil {
+				assert.Empty(t, collected.diags)
+				return
+			}
+			require.Len(t, collected.diags, 1)
+			severity := tst.severity
+			if severity == tfprotov5.DiagnosticSeverityInvalid {
+				severity = tfprotov5.DiagnosticSeverityError
+			}
+			assert.Equal(t, severity, collected.diags[0].Severity)
+			assert.Equal(t, tst.path, collected.diags[0].Attribute)
+			assert.Contains(t, collected.diags[0].Detail, tst.detail)
+		})
+	}
+}
+
+func TestValidateSQLAtPlan_Skip(t *testing.T) {
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	p := Provider()
+	block := schema.InternalMap(p.Schema).CoreConfigSchema()
+	attrs := make(map[string]cty.Value)
+	for name, attr := range block.Attributes {
+		attrs[name] = cty.NullVal(attr.Type)
+	}
+	attrs["skip_sql_validation"] = cty.True
+	c := terraform.NewResourceConfigShimmed(cty.ObjectVal(attrs), block)
+	c.CtyValue = cty.ObjectVal(attrs)
+	require.False(t, p.Configure(context.TODO(), c).HasError())
+
+	collected := &planDiagnostics{}
+	ctx := context.WithValue(context.TODO(), planDiagnosticsKey{}, collected)
+	_, err := p.ResourcesMap["rockset_view"].Diff(ctx, nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace": "commons",
+		"name":      "recent",
+		"query":     "SELEC 1",
+	}), rc)
+	require.NoError(t, err)
+	assert.Empty(t, collected.diags)
+
+	// the references are looked up without validating the SQL
+	_, err = p.ResourcesMap["rockset_view"].Diff(ctx, nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"workspace": "commons",
+		"name":      "recent",
+		"query":     "SELECT * FROM commons.orders",
+	}), rc)
+	require.NoError(t, err)
+	require.Len(t, collected.diags, 1)
+	assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, collected.diags[0].Severity)
+	assert.Equal(t, "query references commons.orders, which doesn't exist", collected.diags[0].Summary)
+}
+
+func TestReferencesMissingObject(t *testing.T) {
+	queryError := func(status int, msg string) error {
+		err := rockerr.NewWithStatusCode(errors.New("apierr"), &http.Response{StatusCode: status})
+		re := err.(rockerr.Error)
+		re.ErrorModel = &openapi.ErrorModel{Message: openapi.PtrString(msg)}
+		return re
+	}
+
+	tests := map[string]bool{
+		"Collection 'commons.orders' not found":        true,
+		"Workspace 'analytics' does not exist":         true,
+		"Alias commons.latest not found":               true,
+		"View 'commons.recent' was not found":          true,
+		"Function 'DATE_TRUC' does not exist":          false,
+		"Field 'ordered_at' not found in the relation": false,
+	}
+	for msg, missing := range tests {
+		assert.Equal(t, missing, referencesMissingObject(queryError(http.StatusBadRequest, msg)), msg)
+	}
+
+	assert.True(t, referencesMissingObject(queryError(http.StatusNotFound, "Not Found")))
+	assert.False(t, referencesMissingObject(errors.New("collection commons.orders not found")))
+}
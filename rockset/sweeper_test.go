@@ -0,0 +1,426 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/rockset/rockset-go-client"
+	rockerr "github.com/rockset/rockset-go-client/errors"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// The sweepers delete the objects which failed acceptance test runs leave behind, and are run with
+//
+//	go test ./rockset -v -sweep=all
+//
+// Only objects with names created by the acceptance tests are deleted, and the dependencies make sure objects are
+// deleted before what they depend on, e.g. collections before their workspace.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+// sweepPrefixes are the prefixes of the names created by the acceptance tests, either by randomName or in the
+// configurations in testdata.
+var sweepPrefixes = []string{testNamePrefix, "terraform-provider-acceptance-"}
+
+func init() {
+	resource.AddTestSweepers("rockset_collection_mount", &resource.Sweeper{
+		Name: "rockset_collection_mount",
+		F:    sweepCollectionMounts,
+	})
+	resource.AddTestSweepers("rockset_virtual_instance", &resource.Sweeper{
+		Name:         "rockset_virtual_instance",
+		Dependencies: []string{"rockset_collection_mount"},
+		F:            sweepVirtualInstances,
+	})
+	resource.AddTestSweepers("rockset_alias", &resource.Sweeper{
+		Name: "rockset_alias",
+		F:    sweepAliases,
+	})
+	resource.AddTestSweepers("rockset_view", &resource.Sweeper{
+		Name: "rockset_view",
+		F:    sweepViews,
+	})
+	resource.AddTestSweepers("rockset_query_lambda", &resource.Sweeper{
+		Name: "rockset_query_lambda",
+		F:    sweepQueryLambdas,
+	})
+	resource.AddTestSweepers("rockset_collection", &resource.Sweeper{
+		Name: "rockset_collection",
+		Dependencies: []string{"rockset_collection_mount", "rockset_alias", "rockset_view",
+			"rockset_query_lambda"},
+		F: sweepCollections,
+	})
+	resource.AddTestSweepers("rockset_integration", &resource.Sweeper{
+		Name:         "rockset_integration",
+		Dependencies: []string{"rockset_collection"},
+		F:            sweepIntegrations,
+	})
+	resource.AddTestSweepers("rockset_workspace", &resource.Sweeper{
+		Name:         "rockset_workspace",
+		Dependencies: []string{"rockset_collection", "rockset_alias", "rockset_view", "rockset_query_lambda"},
+		F:            sweepWorkspaces,
+	})
+	resource.AddTestSweepers("rockset_api_key", &resource.Sweeper{
+		Name: "rockset_api_key",
+		F:    sweepAPIKeys,
+	})
+	resource.AddTestSweepers("rockset_user", &resource.Sweeper{
+		Name: "rockset_user",
+		F:    sweepUsers,
+	})
+	resource.AddTestSweepers("rockset_role", &resource.Sweeper{
+		Name:         "rockset_role",
+		Dependencies: []string{"rockset_user", "rockset_api_key"},
+		F:            sweepRoles,
+	})
+}
+
+// sweepable returns true if the name was created by the acceptance tests.
+func sweepable(names ...string) bool {
+	for _, name := range names {
+		for _, prefix := range sweepPrefixes {
+			if strings.HasPrefix(strings.ToLower(name), prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sweepClient creates a client from the environment, like the provider does when it isn't configured.
+func sweepClient(ctx context.Context) (*rockset.RockClient, error) {
+	rc, err := client.New(ctx, client.Config{Version: "sweeper", Retry: client.DefaultRetryConfig()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Rockset client: %w", err)
+	}
+
+	return rc, nil
+}
+
+// sweepError ignores the error if the object already is gone.
+func sweepError(what string, err error) error {
+	var re rockerr.Error
+	if err == nil || errors.As(err, &re) && re.IsNotFoundError() {
+		return nil
+	}
+
+	return fmt.Errorf("failed to sweep %s: %w", what, err)
+}
+
+func sweepCollectionMounts(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	vis, err := rc.ListVirtualInstances(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, vi := range vis {
+		mounts, err := rc.ListCollectionMounts(ctx, vi.GetId())
+		if err != nil {
+			errs = append(errs, sweepError("mounts of virtual instance "+vi.GetId(), err))
+			continue
+		}
+
+		for _, m := range mounts {
+			ws, coll, _ := strings.Cut(m.GetCollectionPath(), ".")
+			if !sweepable(vi.GetName(), ws, coll) {
+				continue
+			}
+
+			log.Printf("[INFO] unmounting %s from virtual instance %s", m.GetCollectionPath(), vi.GetId())
+			if _, err = rc.UnmountCollection(ctx, vi.GetId(), m.GetCollectionPath()); err == nil {
+				err = rc.Wait.UntilMountGone(ctx, vi.GetId(), ws, coll)
+			}
+			errs = append(errs, sweepError("mount "+m.GetCollectionPath(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepVirtualInstances(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	vis, err := rc.ListVirtualInstances(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, vi := range vis {
+		if vi.GetDefaultVi() || !sweepable(vi.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting virtual instance %s (%s)", vi.GetName(), vi.GetId())
+		if _, err = rc.DeleteVirtualInstance(ctx, vi.GetId()); err == nil {
+			err = rc.Wait.UntilVirtualInstanceGone(ctx, vi.GetId())
+		}
+		errs = append(errs, sweepError("virtual instance "+vi.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepAliases(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	aliases, err := rc.ListAliases(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, a := range aliases {
+		if !sweepable(a.GetWorkspace(), a.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting alias %s.%s", a.GetWorkspace(), a.GetName())
+		if err = rc.DeleteAlias(ctx, a.GetWorkspace(), a.GetName()); err == nil {
+			err = rc.Wait.UntilAliasGone(ctx, a.GetWorkspace(), a.GetName())
+		}
+		errs = append(errs, sweepError("alias "+a.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepViews(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	views, err := rc.ListViews(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, v := range views {
+		if !sweepable(v.GetWorkspace(), v.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting view %s.%s", v.GetWorkspace(), v.GetName())
+		if err = rc.DeleteView(ctx, v.GetWorkspace(), v.GetName()); err == nil {
+			err = rc.Wait.UntilViewGone(ctx, v.GetWorkspace(), v.GetName())
+		}
+		errs = append(errs, sweepError("view "+v.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepQueryLambdas(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	lambdas, err := rc.ListQueryLambdas(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ql := range lambdas {
+		if !sweepable(ql.GetWorkspace(), ql.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting query lambda %s.%s", ql.GetWorkspace(), ql.GetName())
+		err = rc.DeleteQueryLambda(ctx, ql.GetWorkspace(), ql.GetName())
+		errs = append(errs, sweepError("query lambda "+ql.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepCollections(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	collections, err := rc.ListCollections(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, c := range collections {
+		if !sweepable(c.GetWorkspace(), c.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting collection %s.%s", c.GetWorkspace(), c.GetName())
+		if err = rc.DeleteCollection(ctx, c.GetWorkspace(), c.GetName()); err == nil {
+			err = rc.Wait.UntilCollectionGone(ctx, c.GetWorkspace(), c.GetName())
+		}
+		errs = append(errs, sweepError("collection "+c.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepIntegrations(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	integrations, err := rc.ListIntegrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, i := range integrations {
+		if !sweepable(i.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting integration %s", i.GetName())
+		err = rc.DeleteIntegration(ctx, i.GetName())
+		errs = append(errs, sweepError("integration "+i.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepWorkspaces(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	workspaces, err := rc.ListWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ws := range workspaces {
+		if !sweepable(ws.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting workspace %s", ws.GetName())
+		if err = rc.DeleteWorkspace(ctx, ws.GetName()); err == nil {
+			err = rc.Wait.UntilWorkspaceGone(ctx, ws.GetName())
+		}
+		errs = append(errs, sweepError("workspace "+ws.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepAPIKeys(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := rc.ListAPIKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, k := range keys {
+		if !sweepable(k.GetName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting api key %s", k.GetName())
+		err = rc.DeleteAPIKey(ctx, k.GetName())
+		errs = append(errs, sweepError("api key "+k.GetName(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepUsers(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	users, err := rc.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, u := range users {
+		// the tests create users with the email acc+<random name>@rockset.com
+		local, _, _ := strings.Cut(u.GetEmail(), "@")
+		_, name, found := strings.Cut(local, "+")
+		if !found || !sweepable(name) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting user %s", u.GetEmail())
+		err = rc.DeleteUser(ctx, u.GetEmail())
+		errs = append(errs, sweepError("user "+u.GetEmail(), err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func sweepRoles(_ string) error {
+	ctx := context.Background()
+	rc, err := sweepClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	roles, err := rc.ListRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, r := range roles {
+		if !sweepable(r.GetRoleName()) {
+			continue
+		}
+
+		log.Printf("[INFO] deleting role %s", r.GetRoleName())
+		err = rc.DeleteRole(ctx, r.GetRoleName())
+		errs = append(errs, sweepError("role "+r.GetRoleName(), err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,53 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	"github.com/rockset/terraform-provider-rockset/internal/tracing"
+)
+
+// traceOperations creates a span for every CRUD operation of the resources and data sources,
+// which is the parent of the spans of the API calls and wait loops of the operation.
+func traceOperations(p *schema.Provider) {
+	for name, r := range p.ResourcesMap {
+		r.CreateContext = traceOperation(name, "create", r.CreateContext)
+		r.ReadContext = traceOperation(name, "read", r.ReadContext)
+		r.UpdateContext = traceOperation(name, "update", r.UpdateContext)
+		r.DeleteContext = traceOperation(name, "delete", r.DeleteContext)
+	}
+
+	for name, r := range p.DataSourcesMap {
+		r.ReadContext = traceOperation(name, "read", r.ReadContext)
+	}
+}
+
+// traceOperation wraps fn in a span, where F is any of the CRUD function types as they have the same signature.
+func traceOperation[F ~func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics](
+	name, operation string, fn F) F {
+	if fn == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		ctx, span := tracing.StartOperation(ctx, name, operation, d.Id())
+		diags := fn(ctx, d, meta)
+		tracing.EndOperation(span, d.Id(), diagsError(diags))
+
+		return diags
+	}
+}
+
+// diagsError returns the first error of the diagnostics, or nil if there are none.
+func diagsError(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			return errors.New(d.Summary)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package rockset
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/rockset/terraform-provider-rockset/internal/tracing"
+)
+
+func TestTraceOperations(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	c := http.Client{Transport: tracing.NewTransport(nil)}
+
+	p := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"rockset_test": {
+				CreateContext: func(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+"/v1/test", nil)
+					require.NoError(t, err)
+					resp, err := c.Do(req)
+					require.NoError(t, err)
+					resp.Body.Close()
+
+					d.SetId("id")
+					return nil
+				},
+				DeleteContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+					return diag.Errorf("failed")
+				},
+			},
+		},
+	}
+	traceOperations(p)
+
+	r := p.ResourcesMap["rockset_test"]
+	assert.Nil(t, r.UpdateContext)
+
+	d := r.TestResourceData()
+	require.False(t, r.CreateContext(context.TODO(), d, nil).HasError())
+	require.True(t, r.DeleteContext(context.TODO(), d, nil).HasError())
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	httpSpan, create, del := spans[0], spans[1], spans[2]
+	assert.Equal(t, "rockset_test.create", create.Name)
+	assert.Equal(t, "POST /v1/test", httpSpan.Name)
+	assert.Equal(t, create.SpanContext.SpanID(), httpSpan.Parent.SpanID())
+	assert.Contains(t, create.Attributes, tracing.ResourceIDKey.String("id"))
+
+	assert.Equal(t, "rockset_test.delete", del.Name)
+	assert.Equal(t, codes.Error, del.Status.Code)
+	assert.Equal(t, "failed", del.Status.Description)
+}
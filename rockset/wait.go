@@ -0,0 +1,161 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// defaultWaitTimeout is the timeout of operations which wait for an object to change state, unless configured
+// in a timeouts block. It is the same as the default of the SDK, but declaring it makes it configurable.
+const defaultWaitTimeout = 20 * time.Minute
+
+// lastStateTimeout limits how long it takes to read the state of an object after a wait timed out.
+const lastStateTimeout = 30 * time.Second
+
+// stateFunc returns a short description of the current state of an object, e.g. "RESIZING".
+type stateFunc func(ctx context.Context) (string, error)
+
+// waitWithTimeout runs wait with the timeout of the operation, which is one of schema.TimeoutCreate,
+// schema.TimeoutUpdate or schema.TimeoutDelete. When the timeout expires, the returned error says which state
+// the object was last seen in.
+func waitWithTimeout(ctx context.Context, d *schema.ResourceData, operation, object string,
+	wait func(ctx context.Context) error, state stateFunc) error {
+	timeout := d.Timeout(operation)
+	wctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := wait(wctx)
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	sctx, scancel := context.WithTimeout(context.WithoutCancel(ctx), lastStateTimeout)
+	defer scancel()
+
+	last, serr := state(sctx)
+	switch {
+	case serr == nil:
+	case client.IsNotFound(serr):
+		last = "not found"
+	default:
+		tflog.Warn(ctx, "failed to read the state after the wait timed out", map[string]interface{}{
+			"object": object,
+			"error":  serr.Error(),
+		})
+		last = "unknown"
+	}
+
+	return fmt.Errorf("timed out after %s waiting for %s, it was last seen in state %s: %w", timeout, object, last,
+		err)
+}
+
+func collectionState(rc *rockset.RockClient, workspace, name string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		c, err := rc.GetCollection(ctx, workspace, name)
+		if err != nil {
+			return "", err
+		}
+		stats := c.GetStats()
+
+		return fmt.Sprintf("%s with %d documents", c.GetStatus(), stats.GetDocCount()), nil
+	}
+}
+
+func virtualInstanceState(rc *rockset.RockClient, id string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		vi, err := rc.GetVirtualInstance(ctx, id)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s with size %s (desired %s)", vi.GetState(), vi.GetCurrentSize(), vi.GetDesiredSize()),
+			nil
+	}
+}
+
+func mountState(rc *rockset.RockClient, vID, path string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		m, err := rc.GetCollectionMount(ctx, vID, path)
+		if err != nil {
+			return "", err
+		}
+
+		return m.GetState(), nil
+	}
+}
+
+func workspaceState(rc *rockset.RockClient, name string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		ws, err := rc.GetWorkspace(ctx, name)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("present with %d collections", ws.GetCollectionCount()), nil
+	}
+}
+
+func aliasState(rc *rockset.RockClient, workspace, name string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		a, err := rc.GetAlias(ctx, workspace, name)
+		if err != nil {
+			return "", err
+		}
+
+		return a.GetState(), nil
+	}
+}
+
+func viewState(rc *rockset.RockClient, workspace, name string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		v, err := rc.GetView(ctx, workspace, name)
+		if err != nil {
+			return "", err
+		}
+
+		return v.GetState(), nil
+	}
+}
+
+func scheduledLambdaState(rc *rockset.RockClient, workspace, rrn string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		if _, err := rc.GetScheduledLambda(ctx, workspace, rrn); err != nil {
+			return "", err
+		}
+
+		return "present", nil
+	}
+}
+
+// kafkaIntegrationState describes the state of each topic of the integration, e.g. "orders: ACTIVE, users: DORMANT".
+func kafkaIntegrationState(rc *rockset.RockClient, name string) stateFunc {
+	return func(ctx context.Context) (string, error) {
+		i, err := rc.GetIntegration(ctx, name)
+		if err != nil {
+			return "", err
+		}
+
+		topics := i.Kafka.GetSourceStatusByTopic()
+		if len(topics) == 0 {
+			return "without topics", nil
+		}
+
+		states := make([]string, 0, len(topics))
+		for topic, status := range topics {
+			states = append(states, topic+": "+status.GetState())
+		}
+		sort.Strings(states)
+
+		return strings.Join(states, ", "), nil
+	}
+}
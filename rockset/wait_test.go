@@ -0,0 +1,88 @@
+package rockset
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestWaitWithTimeout(t *testing.T) {
+	const defaultVI = "29e4a43c-fff4-4fe6-80e3-1ee57bc22e82"
+	srv := rocksettest.NewServer(rocksettest.WithDefaultVirtualInstanceID(defaultVI))
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	d := resourceVirtualInstance().TestResourceData()
+	blocked := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name  string
+		wait  func(ctx context.Context) error
+		state stateFunc
+		err   string
+	}{
+		{
+			name:  "done",
+			wait:  func(ctx context.Context) error { return nil },
+			state: virtualInstanceState(rc, defaultVI),
+		},
+		{
+			name:  "failed",
+			wait:  func(ctx context.Context) error { return errors.New("bad state") },
+			state: virtualInstanceState(rc, defaultVI),
+			err:   "bad state",
+		},
+		{
+			name:  "timed out",
+			wait:  blocked,
+			state: virtualInstanceState(rc, defaultVI),
+			err: "timed out after 20m0s waiting for the test, it was last seen in state ACTIVE with size SMALL " +
+				"(desired SMALL): context deadline exceeded",
+		},
+		{
+			name:  "timed out and gone",
+			wait:  blocked,
+			state: collectionState(rc, "commons", "missing"),
+			err:   "it was last seen in state not found",
+		},
+		{
+			name: "timed out and the state can't be read",
+			wait: blocked,
+			state: func(ctx context.Context) (string, error) {
+				return "", errors.New("unavailable")
+			},
+			err: "it was last seen in state unknown",
+		},
+	}
+
+	for _, tst := range tests {
+		t.Run(tst.name, func(t *testing.T) {
+			// the SDK sets a deadline on the context of each operation, which is simulated with a short one
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			err := waitWithTimeout(ctx, d, schema.TimeoutCreate, "the test", tst.wait, tst.state)
+			if tst.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tst.err)
+		})
+	}
+
+	_, err := collectionState(rc, "commons", "missing")(context.TODO())
+	assert.True(t, client.IsNotFound(err))
+}
@@ -0,0 +1,205 @@
+package rockset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client"
+	"github.com/rockset/rockset-go-client/option"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+)
+
+// deleteWorkspaceContents deletes everything in the workspace, so the workspace itself can be deleted.
+// Objects are deleted in dependency order, and each step waits until its objects are gone before the next starts.
+// Scheduled lambdas can't be listed through the API, so if a query lambda is still used by one that isn't managed
+// by Terraform, deleting the query lambda fails.
+func deleteWorkspaceContents(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData,
+	workspace string) error {
+	steps := []func(context.Context, *rockset.RockClient, *schema.ResourceData, string) error{
+		deleteWorkspaceQueryLambdas,
+		deleteWorkspaceViews,
+		deleteWorkspaceAliases,
+		unmountWorkspaceCollections,
+		deleteWorkspaceCollections,
+	}
+
+	for _, step := range steps {
+		if err := step(ctx, rc, d, workspace); err != nil {
+			return fmt.Errorf("failed to empty workspace %s: %w", workspace, err)
+		}
+	}
+
+	return nil
+}
+
+func deleteWorkspaceQueryLambdas(ctx context.Context, rc *rockset.RockClient, _ *schema.ResourceData,
+	workspace string) error {
+	lambdas, err := rc.ListQueryLambdas(ctx, option.WithQueryLambdaWorkspace(workspace))
+	if err != nil {
+		return err
+	}
+
+	for _, ql := range lambdas {
+		tflog.Info(ctx, "force destroy: deleting query lambda", map[string]interface{}{
+			"workspace": workspace,
+			"name":      ql.GetName(),
+		})
+		if err = rc.DeleteQueryLambda(ctx, workspace, ql.GetName()); err != nil && !client.IsNotFound(err) {
+			return fmt.Errorf("failed to delete query lambda %s: %w", toID(workspace, ql.GetName()), err)
+		}
+	}
+
+	// query lambdas are deleted synchronously, so there is nothing to wait for
+	return nil
+}
+
+func deleteWorkspaceViews(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData,
+	workspace string) error {
+	views, err := rc.ListViews(ctx, option.WithViewWorkspace(workspace))
+	if err != nil {
+		return err
+	}
+
+	for _, v := range views {
+		tflog.Info(ctx, "force destroy: deleting view", map[string]interface{}{
+			"workspace": workspace,
+			"name":      v.GetName(),
+		})
+		if err = rc.DeleteView(ctx, workspace, v.GetName()); err != nil && !client.IsNotFound(err) {
+			return fmt.Errorf("failed to delete view %s: %w", toID(workspace, v.GetName()), err)
+		}
+	}
+
+	for _, v := range views {
+		name := v.GetName()
+		err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "view "+toID(workspace, name)+" to be deleted",
+			func(ctx context.Context) error {
+				return rc.Wait.UntilViewGone(ctx, workspace, name)
+			}, viewState(rc, workspace, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteWorkspaceAliases(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData,
+	workspace string) error {
+	aliases, err := rc.ListAliases(ctx, option.WithAliasWorkspace(workspace))
+	if err != nil {
+		return err
+	}
+
+	for _, a := range aliases {
+		tflog.Info(ctx, "force destroy: deleting alias", map[string]interface{}{
+			"workspace": workspace,
+			"name":      a.GetName(),
+		})
+		if err = rc.DeleteAlias(ctx, workspace, a.GetName()); err != nil && !client.IsNotFound(err) {
+			return fmt.Errorf("failed to delete alias %s: %w", toID(workspace, a.GetName()), err)
+		}
+	}
+
+	for _, a := range aliases {
+		name := a.GetName()
+		err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "alias "+toID(workspace, name)+" to be deleted",
+			func(ctx context.Context) error {
+				return rc.Wait.UntilAliasGone(ctx, workspace, name)
+			}, aliasState(rc, workspace, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unmountWorkspaceCollections unmounts the collections of the workspace from all virtual instances.
+func unmountWorkspaceCollections(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData,
+	workspace string) error {
+	instances, err := rc.ListVirtualInstances(ctx)
+	if err != nil {
+		return err
+	}
+
+	type mount struct{ vID, path string }
+	var mounts []mount
+	for _, vi := range instances {
+		list, err := rc.ListCollectionMounts(ctx, vi.GetId())
+		if err != nil {
+			return fmt.Errorf("failed to list mounts of virtual instance %s: %w", vi.GetId(), err)
+		}
+
+		for _, m := range list {
+			if !strings.HasPrefix(m.GetCollectionPath(), workspace+".") {
+				continue
+			}
+
+			tflog.Info(ctx, "force destroy: unmounting collection", map[string]interface{}{
+				"virtual_instance_id": vi.GetId(),
+				"path":                m.GetCollectionPath(),
+			})
+			_, err = rc.UnmountCollection(ctx, vi.GetId(), m.GetCollectionPath())
+			if err != nil && !client.IsNotFound(err) {
+				return fmt.Errorf("failed to unmount %s from virtual instance %s: %w", m.GetCollectionPath(),
+					vi.GetId(), err)
+			}
+			mounts = append(mounts, mount{vID: vi.GetId(), path: m.GetCollectionPath()})
+		}
+	}
+
+	for _, m := range mounts {
+		m := m
+		err = waitWithTimeout(ctx, d, schema.TimeoutDelete,
+			fmt.Sprintf("%s to be unmounted from virtual instance %s", m.path, m.vID),
+			func(ctx context.Context) error {
+				ws, name := workspaceAndNameFromID(m.path)
+				return rc.Wait.UntilMountGone(ctx, m.vID, ws, name)
+			}, mountState(rc, m.vID, m.path))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteWorkspaceCollections(ctx context.Context, rc *rockset.RockClient, d *schema.ResourceData,
+	workspace string) error {
+	collections, err := rc.ListCollections(ctx, option.WithWorkspace(workspace))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range collections {
+		if c.GetStatus() == "DELETED" {
+			continue
+		}
+
+		tflog.Info(ctx, "force destroy: deleting collection", map[string]interface{}{
+			"workspace": workspace,
+			"name":      c.GetName(),
+		})
+		if err = rc.DeleteCollection(ctx, workspace, c.GetName()); err != nil && !client.IsNotFound(err) {
+			return fmt.Errorf("failed to delete collection %s: %w", toID(workspace, c.GetName()), err)
+		}
+	}
+
+	for _, c := range collections {
+		name := c.GetName()
+		err = waitWithTimeout(ctx, d, schema.TimeoutDelete, "collection "+toID(workspace, name)+" to be deleted",
+			func(ctx context.Context) error {
+				return rc.Wait.UntilCollectionGone(ctx, workspace, name)
+			}, collectionState(rc, workspace, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
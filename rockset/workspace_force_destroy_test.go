@@ -0,0 +1,66 @@
+package rockset
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/rockset/rockset-go-client/openapi"
+	"github.com/rockset/rockset-go-client/option"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rockset/terraform-provider-rockset/internal/client"
+	"github.com/rockset/terraform-provider-rockset/internal/rocksettest"
+)
+
+func TestWorkspaceForceDestroy(t *testing.T) {
+	ctx := context.TODO()
+	srv := rocksettest.NewServer()
+	defer srv.Close()
+
+	rc := rocksettest.NewClient(t, srv)
+
+	_, err := rc.CreateWorkspace(ctx, "ephemeral")
+	require.NoError(t, err)
+	_, err = rc.CreateCollection(ctx, "ephemeral", "orders", option.WithCollectionRequest(openapi.CreateCollectionRequest{
+		Name: openapi.PtrString("orders"),
+	}))
+	require.NoError(t, err)
+	require.NoError(t, rc.Wait.UntilCollectionReady(ctx, "ephemeral", "orders"))
+	_, err = rc.CreateAlias(ctx, "ephemeral", "current", []string{"ephemeral.orders"})
+	require.NoError(t, err)
+	_, err = rc.CreateView(ctx, "ephemeral", "recent", "SELECT * FROM ephemeral.orders")
+	require.NoError(t, err)
+	_, err = rc.CreateQueryLambda(ctx, "ephemeral", "count", "SELECT COUNT(*) FROM ephemeral.orders")
+	require.NoError(t, err)
+
+	instances, err := rc.ListVirtualInstances(ctx)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	_, err = rc.MountCollections(ctx, instances[0].GetId(), []string{"ephemeral.orders"})
+	require.NoError(t, err)
+
+	newData := func(forceDestroy bool) *schema.ResourceData {
+		d := schema.TestResourceDataRaw(t, resourceWorkspace().Schema, map[string]interface{}{
+			"name":          "ephemeral",
+			"force_destroy": forceDestroy,
+		})
+		d.SetId("ephemeral")
+		return d
+	}
+
+	diags := resourceWorkspaceDelete(ctx, newData(false), rc)
+	require.True(t, diags.HasError())
+	_, err = rc.GetWorkspace(ctx, "ephemeral")
+	require.NoError(t, err)
+
+	diags = resourceWorkspaceDelete(ctx, newData(true), rc)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	_, err = rc.GetWorkspace(ctx, "ephemeral")
+	assert.True(t, client.IsNotFound(err), "workspace should be gone, got %v", err)
+	mounts, err := rc.ListCollectionMounts(ctx, instances[0].GetId())
+	require.NoError(t, err)
+	assert.Empty(t, mounts)
+}